@@ -3,11 +3,14 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
 	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
 	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
@@ -22,6 +25,9 @@ import (
 const RoundLatest = coreClient.RoundLatest
 
 // RuntimeClient is a client interface for runtimes based on the Oasis Runtime SDK.
+//
+// A RuntimeClient is safe for concurrent use by multiple goroutines and so are the module helpers
+// built on top of it. A TransactionBuilder is not safe for concurrent use.
 type RuntimeClient interface {
 	// GetInfo returns information about the runtime.
 	GetInfo(ctx context.Context) (*types.RuntimeInfo, error)
@@ -54,16 +60,39 @@ type RuntimeClient interface {
 	// in a block.
 	SubmitTxMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*SubmitTxMeta, error)
 
+	// SubmitTxMetaDecoded submits a transaction to the runtime transaction scheduler, waits for
+	// transaction execution results and decodes the call result into rsp (if not nil).
+	//
+	// In case the transaction failed the transaction check, the returned metadata has CheckTxError
+	// set and no error is returned. In case the call failed, the failure is returned as a
+	// *types.FailedCallResult error and rsp is left untouched.
+	SubmitTxMetaDecoded(ctx context.Context, tx *types.UnverifiedTransaction, rsp interface{}) (*TransactionMeta, error)
+
 	// SubmitTxNoWait submits a transaction to the runtime transaction scheduler but does
 	// not wait for transaction execution.
 	SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error
 
+	// WaitForTxResults waits for the results of the transactions with the given hashes (see
+	// types.UnverifiedTransaction.Hash) by scanning blocks starting at fromRound and following new
+	// blocks. A zero timeout means that only the context limits how long to wait.
+	//
+	// To avoid missing transactions, fromRound should be the round following the latest block
+	// obtained before the transactions were submitted (e.g. via SubmitTxNoWait). In case fromRound
+	// is RoundLatest, only blocks from the latest block at the time of the call are scanned. In
+	// case not all results were found before the timeout expired, the results found so far are
+	// returned together with an error.
+	WaitForTxResults(ctx context.Context, hashes []hash.Hash, fromRound uint64, timeout time.Duration) (map[hash.Hash]*TxResult, error)
+
 	// GetGenesisBlock returns the genesis block.
 	GetGenesisBlock(ctx context.Context) (*block.Block, error)
 
 	// GetBlock fetches the given runtime block.
 	GetBlock(ctx context.Context, round uint64) (*block.Block, error)
 
+	// RoundTimestamp returns the wall-clock time of the given round, as recorded in its block
+	// header. Blocks received via WatchBlocks already carry the same timestamp in their header.
+	RoundTimestamp(ctx context.Context, round uint64) (time.Time, error)
+
 	// GetLastRetainedBlock returns the last retained block.
 	GetLastRetainedBlock(ctx context.Context) (*block.Block, error)
 
@@ -81,9 +110,17 @@ type RuntimeClient interface {
 	GetEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool) ([]DecodedEvent, error)
 
 	// WatchBlocks subscribes to blocks for a specific runtimes.
+	//
+	// In case the client was created with WithAutoReconnect, the subscription survives transient
+	// disconnects and blocks missed while disconnected are delivered before newer ones. Blocks
+	// fetched after reconnecting have no consensus height set. Subscribers are not notified of
+	// reconnects; use WatchEvents in case that is required.
 	WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error)
 
 	// WatchEvents subscribes and decodes runtime events.
+	//
+	// In case the client was created with WithAutoReconnect, the subscription survives transient
+	// disconnects and the first block delivered after reconnecting has Reconnected set.
 	WatchEvents(ctx context.Context, decoders []EventDecoder, includeUndecoded bool) (<-chan *BlockEvents, error)
 
 	// Query makes a runtime-specific query.
@@ -106,6 +143,11 @@ type BlockEvents struct {
 
 	// Events are the decoded events.
 	Events []DecodedEvent
+
+	// Reconnected is true for the first block delivered after the subscription has been
+	// re-established following a disconnect. Any blocks missed while disconnected have already
+	// been delivered when this block is received.
+	Reconnected bool
 }
 
 // TransactionMeta are the metadata about transaction execution.
@@ -126,6 +168,21 @@ type CheckTxError struct {
 	Message string
 }
 
+// Error is a trivial implementation of error.
+func (e *CheckTxError) Error() string {
+	return fmt.Sprintf("check tx failed (module: %s code: %d message: %s)", e.Module, e.Code, e.Message)
+}
+
+// TxResult is the result of a transaction returned by WaitForTxResults.
+type TxResult struct {
+	TransactionMeta
+
+	// Result is the call result.
+	Result types.CallResult
+	// Events are the events emitted by the transaction.
+	Events []*types.Event
+}
+
 // SubmitTxRawMeta is the result of SubmitTxRawMeta call.
 type SubmitTxRawMeta struct {
 	TransactionMeta
@@ -144,6 +201,9 @@ type SubmitTxMeta struct {
 
 // TransactionWithResults is an SDK transaction together with its results and emitted events.
 type TransactionWithResults struct {
+	// Hash is the hash of the raw transaction.
+	Hash hash.Hash
+
 	Tx     types.UnverifiedTransaction
 	Result types.CallResult
 	Events []*types.Event
@@ -153,16 +213,37 @@ type runtimeClient struct {
 	cs consensus.ClientBackend
 	cc coreClient.RuntimeClient
 
-	runtimeID   common.Namespace
+	runtimeID common.Namespace
+
+	infoLock    sync.Mutex
 	runtimeInfo *types.RuntimeInfo
+
+	maxReconnectBackoff time.Duration
+	queryTimeout        time.Duration
+	metrics             Metrics
+}
+
+// queryContext derives a context for a single query, applying the configured query timeout in
+// case the given context has no deadline.
+func (rc *runtimeClient) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || rc.queryTimeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, rc.queryTimeout)
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	rc.infoLock.Lock()
+	defer rc.infoLock.Unlock()
+
 	if rc.runtimeInfo != nil {
 		return rc.runtimeInfo, nil
 	}
 
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	chainCtx, err := rc.cs.GetChainContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch consensus layer chain context: %w", err)
@@ -177,6 +258,18 @@ func (rc *runtimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+	start := time.Now()
+	result, err := rc.submitTxRaw(ctx, tx)
+	switch {
+	case err != nil:
+		rc.observeSubmit(tx, start, err)
+	default:
+		rc.observeSubmit(tx, start, callResultError(result))
+	}
+	return result, err
+}
+
+func (rc *runtimeClient) submitTxRaw(ctx context.Context, tx *types.UnverifiedTransaction) (*types.CallResult, error) {
 	raw, err := rc.cc.SubmitTx(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
@@ -194,6 +287,20 @@ func (rc *runtimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTr
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxRawMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*SubmitTxRawMeta, error) {
+	start := time.Now()
+	meta, err := rc.submitTxRawMeta(ctx, tx)
+	switch {
+	case err != nil:
+		rc.observeSubmit(tx, start, err)
+	case meta.CheckTxError != nil:
+		rc.observeSubmit(tx, start, meta.CheckTxError)
+	default:
+		rc.observeSubmit(tx, start, callResultError(&meta.Result))
+	}
+	return meta, err
+}
+
+func (rc *runtimeClient) submitTxRawMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*SubmitTxRawMeta, error) {
 	meta, err := rc.cc.SubmitTxMeta(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
@@ -269,39 +376,107 @@ func (rc *runtimeClient) SubmitTxMeta(ctx context.Context, tx *types.UnverifiedT
 	}
 }
 
+// Implements RuntimeClient.
+func (rc *runtimeClient) SubmitTxMetaDecoded(ctx context.Context, tx *types.UnverifiedTransaction, rsp interface{}) (*TransactionMeta, error) {
+	meta, err := rc.SubmitTxMeta(ctx, tx)
+	switch {
+	case meta == nil:
+		return nil, err
+	case err != nil:
+		return &meta.TransactionMeta, err
+	case meta.CheckTxError != nil:
+		return &meta.TransactionMeta, nil
+	}
+
+	if rsp != nil {
+		if err = cbor.Unmarshal(meta.Result, rsp); err != nil {
+			return &meta.TransactionMeta, fmt.Errorf("failed to unmarshal call result: %w", err)
+		}
+	}
+	return &meta.TransactionMeta, nil
+}
+
 // Implements RuntimeClient.
 func (rc *runtimeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
-	return rc.cc.SubmitTxNoWait(ctx, &coreClient.SubmitTxRequest{
+	start := time.Now()
+	err := rc.cc.SubmitTxNoWait(ctx, &coreClient.SubmitTxRequest{
 		RuntimeID: rc.runtimeID,
 		Data:      cbor.Marshal(tx),
 	})
+	rc.observeSubmit(tx, start, err)
+	return err
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
-	return rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	if rc.maxReconnectBackoff == 0 {
+		return rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	}
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	blkCh, err := rc.watchBlocks(ctx)
+	if err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+
+	ch := make(chan *roothash.AnnotatedBlock)
+	go func() {
+		defer close(ch)
+
+		for wb := range blkCh {
+			select {
+			case ch <- wb.blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	return rc.cc.GetGenesisBlock(ctx, rc.runtimeID)
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	return rc.cc.GetBlock(ctx, &coreClient.GetBlockRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
 	})
 }
 
+// Implements RuntimeClient.
+func (rc *runtimeClient) RoundTimestamp(ctx context.Context, round uint64) (time.Time, error) {
+	blk, err := rc.GetBlock(ctx, round)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(blk.Header.Timestamp), 0), nil
+}
+
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetLastRetainedBlock(ctx context.Context) (*block.Block, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	return rc.cc.GetLastRetainedBlock(ctx, rc.runtimeID)
 }
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	rawTxs, err := rc.cc.GetTransactions(ctx, &coreClient.GetTransactionsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
@@ -321,6 +496,9 @@ func (rc *runtimeClient) GetTransactions(ctx context.Context, round uint64) ([]*
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*TransactionWithResults, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	rawTxs, err := rc.cc.GetTransactionsWithResults(ctx, &coreClient.GetTransactionsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
@@ -331,7 +509,9 @@ func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round u
 
 	txs := make([]*TransactionWithResults, len(rawTxs))
 	for i, raw := range rawTxs {
-		var tx TransactionWithResults
+		tx := TransactionWithResults{
+			Hash: hash.NewFromBytes(raw.Tx),
+		}
 		_ = cbor.Unmarshal(raw.Tx, &tx.Tx) // Ignore errors as there can be invalid transactions.
 		_ = cbor.Unmarshal(raw.Result, &tx.Result)
 
@@ -351,6 +531,9 @@ func (rc *runtimeClient) GetTransactionsWithResults(ctx context.Context, round u
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	rawEvs, err := rc.cc.GetEvents(ctx, &coreClient.GetEventsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
@@ -373,6 +556,9 @@ func (rc *runtimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*typ
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) GetEvents(ctx context.Context, round uint64, decoders []EventDecoder, includeUndecoded bool) ([]DecodedEvent, error) {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	rawEvs, err := rc.cc.GetEvents(ctx, &coreClient.GetEventsRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
@@ -408,34 +594,37 @@ OUTER:
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) WatchEvents(ctx context.Context, decoders []EventDecoder, includeUndecoded bool) (<-chan *BlockEvents, error) {
-	ch := make(chan *BlockEvents)
-
-	blkCh, blkSub, err := rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	blkCh, err := rc.watchBlocks(ctx)
 	if err != nil {
+		sub.Close()
 		return nil, err
 	}
 
+	ch := make(chan *BlockEvents)
 	go func() {
-		defer blkSub.Close()
+		defer sub.Close()
 		defer close(ch)
 
-		for {
+		for wb := range blkCh {
+			round := wb.blk.Block.Header.Round
+
+			var events []DecodedEvent
+			if rerr := rc.retry(ctx, func() (gerr error) {
+				events, gerr = rc.GetEvents(ctx, round, decoders, includeUndecoded)
+				return
+			}); rerr != nil {
+				return
+			}
+
 			select {
+			case ch <- &BlockEvents{
+				Round:       round,
+				Events:      events,
+				Reconnected: wb.reconnected,
+			}:
 			case <-ctx.Done():
 				return
-			case blk, ok := <-blkCh:
-				if !ok {
-					return
-				}
-
-				events, err := rc.GetEvents(ctx, blk.Block.Header.Round, decoders, includeUndecoded)
-				if err != nil {
-					return
-				}
-				ch <- &BlockEvents{
-					Round:  blk.Block.Header.Round,
-					Events: events,
-				}
 			}
 		}
 	}()
@@ -445,6 +634,16 @@ func (rc *runtimeClient) WatchEvents(ctx context.Context, decoders []EventDecode
 
 // Implements RuntimeClient.
 func (rc *runtimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	start := time.Now()
+	err := rc.query(ctx, round, method, args, rsp)
+	rc.observe(OperationQuery, method, start, err)
+	return err
+}
+
+func (rc *runtimeClient) query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	ctx, cancel := rc.queryContext(ctx)
+	defer cancel()
+
 	raw, err := rc.cc.Query(ctx, &coreClient.QueryRequest{
 		RuntimeID: rc.runtimeID,
 		Round:     round,
@@ -462,11 +661,39 @@ func (rc *runtimeClient) Query(ctx context.Context, round uint64, method string,
 	return nil
 }
 
+// Option is a runtime client option.
+type Option func(*runtimeClient)
+
+// WithAutoReconnect configures the client to transparently re-establish block and event
+// subscriptions after transient disconnects instead of closing the subscription channel.
+//
+// Reconnection attempts use exponential backoff capped at maxBackoff. Only WatchEvents reports
+// reconnects to subscribers (see BlockEvents.Reconnected).
+func WithAutoReconnect(maxBackoff time.Duration) Option {
+	return func(rc *runtimeClient) {
+		rc.maxReconnectBackoff = maxBackoff
+	}
+}
+
+// WithQueryTimeout configures a timeout applied to each query (e.g. GetInfo, Query, GetBlock,
+// GetEvents) for which the caller's context does not already specify a deadline.
+//
+// Transaction submission and subscriptions are not affected.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(rc *runtimeClient) {
+		rc.queryTimeout = timeout
+	}
+}
+
 // New creates a new runtime client for the specified runtime.
-func New(conn *grpc.ClientConn, runtimeID common.Namespace) RuntimeClient {
-	return &runtimeClient{
+func New(conn *grpc.ClientConn, runtimeID common.Namespace, opts ...Option) RuntimeClient {
+	rc := &runtimeClient{
 		cs:        consensus.NewConsensusClient(conn),
 		cc:        coreClient.NewRuntimeClient(conn),
 		runtimeID: runtimeID,
 	}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	return rc
 }
@@ -0,0 +1,434 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	consensus "github.com/oasisprotocol/oasis-core/go/consensus/api"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+	"github.com/oasisprotocol/oasis-core/go/runtime/host/protocol"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+type mockConsensus struct {
+	consensus.ClientBackend
+
+	calls       uint32
+	hasDeadline bool
+}
+
+func (mc *mockConsensus) GetChainContext(ctx context.Context) (string, error) {
+	atomic.AddUint32(&mc.calls, 1)
+	_, mc.hasDeadline = ctx.Deadline()
+	return "test chain context", nil
+}
+
+func TestGetInfoConcurrent(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	mc := &mockConsensus{}
+	rc := &runtimeClient{
+		cs:        mc,
+		runtimeID: runtimeID,
+	}
+
+	const numWorkers = 16
+	var wg sync.WaitGroup
+	infos := make([]*types.RuntimeInfo, numWorkers)
+	errs := make([]error, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			infos[i], errs[i] = rc.GetInfo(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < numWorkers; i++ {
+		require.NoError(errs[i], "GetInfo")
+		require.Same(infos[0], infos[i], "all callers should observe the same cached runtime info")
+	}
+	require.EqualValues(1, atomic.LoadUint32(&mc.calls), "chain context should only be fetched once")
+}
+
+type mockCoreClient struct {
+	coreClient.RuntimeClient
+
+	hasDeadline bool
+	requestID   string
+
+	submitMeta *coreClient.SubmitTxMetaResponse
+	submitErr  error
+}
+
+func (mc *mockCoreClient) Query(ctx context.Context, request *coreClient.QueryRequest) (*coreClient.QueryResponse, error) {
+	_, mc.hasDeadline = ctx.Deadline()
+	mc.requestID, _ = RequestIDFromContext(ctx)
+	return &coreClient.QueryResponse{}, nil
+}
+
+func (mc *mockCoreClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	_, mc.hasDeadline = ctx.Deadline()
+	return nil, nil
+}
+
+func (mc *mockCoreClient) SubmitTxMeta(ctx context.Context, request *coreClient.SubmitTxRequest) (*coreClient.SubmitTxMetaResponse, error) {
+	return mc.submitMeta, mc.submitErr
+}
+
+func (mc *mockCoreClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	var blk block.Block
+	blk.Header.Round = request.Round
+	blk.Header.Timestamp = block.Timestamp(1_600_000_000 + request.Round)
+	return &blk, nil
+}
+
+func TestRoundTimestamp(t *testing.T) {
+	require := require.New(t)
+
+	rc := &runtimeClient{cc: &mockCoreClient{}}
+	ts, err := rc.RoundTimestamp(context.Background(), 42)
+	require.NoError(err, "RoundTimestamp")
+	require.EqualValues(1_600_000_042, ts.Unix())
+}
+
+func TestQueryTimeout(t *testing.T) {
+	require := require.New(t)
+
+	mc := &mockCoreClient{}
+	rc := &runtimeClient{cc: mc}
+	err := rc.Query(context.Background(), RoundLatest, "test.Query", nil, nil)
+	require.NoError(err, "Query")
+	require.False(mc.hasDeadline, "query should not have a deadline by default")
+	_, err = rc.GetEvents(context.Background(), 1, nil, true)
+	require.NoError(err, "GetEvents")
+	require.False(mc.hasDeadline, "GetEvents should not have a deadline by default")
+
+	WithQueryTimeout(time.Minute)(rc)
+	err = rc.Query(context.Background(), RoundLatest, "test.Query", nil, nil)
+	require.NoError(err, "Query")
+	require.True(mc.hasDeadline, "query should have a deadline when a timeout is configured")
+
+	mc.hasDeadline = false
+	_, err = rc.GetEvents(context.Background(), 1, nil, true)
+	require.NoError(err, "GetEvents")
+	require.True(mc.hasDeadline, "GetEvents should have a deadline when a timeout is configured")
+
+	ms := &mockConsensus{}
+	rc.cs = ms
+	_, err = rc.GetInfo(context.Background())
+	require.NoError(err, "GetInfo")
+	require.True(ms.hasDeadline, "GetInfo should have a deadline when a timeout is configured")
+}
+
+func TestSubmitTxMetaDecoded(t *testing.T) {
+	require := require.New(t)
+
+	tx := &types.UnverifiedTransaction{Body: []byte("tx"), AuthProofs: []types.AuthProof{}}
+	failed := &types.FailedCallResult{Module: "accounts", Code: 2, Message: "insufficient balance"}
+
+	for _, tc := range []struct {
+		name        string
+		submitMeta  *coreClient.SubmitTxMetaResponse
+		expectedRsp uint64
+		expectedErr error
+		checkTxErr  *CheckTxError
+	}{
+		{
+			name: "success",
+			submitMeta: &coreClient.SubmitTxMetaResponse{
+				Output:     cbor.Marshal(&types.CallResult{Ok: cbor.Marshal(uint64(42))}),
+				Round:      5,
+				BatchOrder: 2,
+			},
+			expectedRsp: 42,
+		},
+		{
+			name: "failed call",
+			submitMeta: &coreClient.SubmitTxMetaResponse{
+				Output:     cbor.Marshal(&types.CallResult{Failed: failed}),
+				Round:      5,
+				BatchOrder: 2,
+			},
+			expectedErr: failed,
+		},
+		{
+			name: "check tx error",
+			submitMeta: &coreClient.SubmitTxMetaResponse{
+				CheckTxError: &protocol.Error{Module: "core", Code: 5, Message: "invalid nonce"},
+			},
+			checkTxErr: &CheckTxError{Module: "core", Code: 5, Message: "invalid nonce"},
+		},
+	} {
+		rc := &runtimeClient{cc: &mockCoreClient{submitMeta: tc.submitMeta}}
+
+		var rsp uint64
+		meta, err := rc.SubmitTxMetaDecoded(context.Background(), tx, &rsp)
+		require.NotNil(meta, tc.name)
+		require.Equal(tc.expectedRsp, rsp, tc.name)
+		require.Equal(tc.checkTxErr, meta.CheckTxError, tc.name)
+		switch tc.expectedErr {
+		case nil:
+			require.NoError(err, tc.name)
+		default:
+			var fcr *types.FailedCallResult
+			require.True(errors.As(err, &fcr), tc.name)
+			require.Equal(tc.expectedErr, fcr, tc.name)
+		}
+		if tc.checkTxErr == nil {
+			require.EqualValues(5, meta.Round, tc.name)
+			require.EqualValues(2, meta.BatchOrder, tc.name)
+		}
+	}
+
+	// Malformed results should fail decoding.
+	rc := &runtimeClient{cc: &mockCoreClient{submitMeta: &coreClient.SubmitTxMetaResponse{
+		Output: cbor.Marshal(&types.CallResult{Ok: cbor.Marshal("not a number")}),
+	}}}
+	var rsp uint64
+	_, err := rc.SubmitTxMetaDecoded(context.Background(), tx, &rsp)
+	require.Error(err, "SubmitTxMetaDecoded should fail on malformed results")
+
+	// Transport errors should not return any meta.
+	rc = &runtimeClient{cc: &mockCoreClient{submitErr: fmt.Errorf("connection refused")}}
+	meta, err := rc.SubmitTxMetaDecoded(context.Background(), tx, &rsp)
+	require.Error(err, "SubmitTxMetaDecoded should propagate submission errors")
+	require.Nil(meta, "no meta should be returned on submission errors")
+}
+
+type mockEventDecoder struct {
+	module string
+}
+
+func (md *mockEventDecoder) DecodeEvent(event *types.Event) (DecodedEvent, error) {
+	return md.module, nil
+}
+
+func TestDecodeAnyEvent(t *testing.T) {
+	require := require.New(t)
+
+	RegisterEventDecoder("test_decoder", &mockEventDecoder{"test_decoder"})
+	RegisterEventDecoder("test_decoder.sub", &mockEventDecoder{"test_decoder.sub"})
+	require.Panics(func() {
+		RegisterEventDecoder("test_decoder", &mockEventDecoder{})
+	}, "registering a duplicate decoder should panic")
+
+	for _, tc := range []struct {
+		module   string
+		expected DecodedEvent
+	}{
+		{"test_decoder", "test_decoder"},
+		{"test_decoder.other", "test_decoder"},
+		{"test_decoder.sub", "test_decoder.sub"},
+		{"test_decoder.sub.deeper", "test_decoder.sub"},
+		{"test_decoder_other", nil},
+		{"unknown", nil},
+	} {
+		ev, err := DecodeAnyEvent(&types.Event{Module: tc.module})
+		require.NoError(err, "DecodeAnyEvent")
+		require.Equal(tc.expected, ev, tc.module)
+	}
+}
+
+type mockBlocksClient struct {
+	coreClient.RuntimeClient
+
+	txs map[uint64][][]byte
+	// watchFrom is the first round delivered by WatchBlocks (defaults to 1).
+	watchFrom uint64
+}
+
+func (mc *mockBlocksClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	ch := make(chan *roothash.AnnotatedBlock, len(mc.txs))
+	round := mc.watchFrom
+	if round == 0 {
+		round = 1
+	}
+	for ; round <= uint64(len(mc.txs)); round++ {
+		var blk block.Block
+		blk.Header.Round = round
+		ch <- &roothash.AnnotatedBlock{Block: &blk}
+	}
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+func (mc *mockBlocksClient) GetTransactionsWithResults(ctx context.Context, request *coreClient.GetTransactionsRequest) ([]*coreClient.TransactionWithResults, error) {
+	var txs []*coreClient.TransactionWithResults
+	for _, raw := range mc.txs[request.Round] {
+		txs = append(txs, &coreClient.TransactionWithResults{
+			Tx:     raw,
+			Result: cbor.Marshal(&types.CallResult{Ok: cbor.Marshal(nil)}),
+		})
+	}
+	return txs, nil
+}
+
+func TestWaitForTxResults(t *testing.T) {
+	require := require.New(t)
+
+	newTx := func(body string) *types.UnverifiedTransaction {
+		return &types.UnverifiedTransaction{Body: []byte(body), AuthProofs: []types.AuthProof{}}
+	}
+	tx1, tx2, tx3, tx4 := newTx("tx1"), newTx("tx2"), newTx("tx3"), newTx("tx4")
+
+	mc := &mockBlocksClient{
+		txs: map[uint64][][]byte{
+			1: {cbor.Marshal(tx1)},
+			2: {cbor.Marshal(tx3), cbor.Marshal(tx2)},
+		},
+	}
+	rc := &runtimeClient{cc: mc}
+
+	results, err := rc.WaitForTxResults(context.Background(), []hash.Hash{tx1.Hash(), tx2.Hash()}, RoundLatest, time.Minute)
+	require.NoError(err, "WaitForTxResults")
+	require.Len(results, 2)
+	require.EqualValues(1, results[tx1.Hash()].Round)
+	require.EqualValues(0, results[tx1.Hash()].BatchOrder)
+	require.EqualValues(2, results[tx2.Hash()].Round)
+	require.EqualValues(1, results[tx2.Hash()].BatchOrder)
+	require.True(results[tx2.Hash()].Result.IsSuccess(), "transaction should succeed")
+
+	results, err = rc.WaitForTxResults(context.Background(), []hash.Hash{tx1.Hash(), tx4.Hash()}, RoundLatest, 100*time.Millisecond)
+	require.Error(err, "WaitForTxResults should fail when not all results are found")
+	require.Len(results, 1, "results found so far should be returned")
+	require.Contains(results, tx1.Hash())
+
+	// Transactions included before the subscription starts are only found from the start round.
+	mc.txs[3] = [][]byte{cbor.Marshal(tx4)}
+	mc.watchFrom = 3
+	results, err = rc.WaitForTxResults(context.Background(), []hash.Hash{tx1.Hash(), tx4.Hash()}, RoundLatest, 100*time.Millisecond)
+	require.Error(err, "WaitForTxResults should not find transactions included before the latest block")
+	require.Len(results, 1)
+	require.Contains(results, tx4.Hash())
+
+	results, err = rc.WaitForTxResults(context.Background(), []hash.Hash{tx1.Hash(), tx2.Hash(), tx4.Hash()}, 1, time.Minute)
+	require.NoError(err, "WaitForTxResults from a start round")
+	require.Len(results, 3)
+	require.EqualValues(1, results[tx1.Hash()].Round)
+	require.EqualValues(2, results[tx2.Hash()].Round)
+	require.EqualValues(3, results[tx4.Hash()].Round)
+
+	results, err = rc.WaitForTxResults(context.Background(), []hash.Hash{tx1.Hash()}, 2, 100*time.Millisecond)
+	require.Error(err, "WaitForTxResults should not scan rounds before the start round")
+	require.Empty(results)
+}
+
+func TestRequestID(t *testing.T) {
+	require := require.New(t)
+
+	_, ok := RequestIDFromContext(context.Background())
+	require.False(ok, "there should be no request ID by default")
+
+	ctx := WithRequestID(context.Background(), "first")
+	ctx = WithRequestID(ctx, "second")
+	id, ok := RequestIDFromContext(ctx)
+	require.True(ok, "request ID should be attached")
+	require.Equal("second", id, "request ID should be replaced")
+
+	mc := &mockCoreClient{}
+	rc := &runtimeClient{cc: mc}
+	WithQueryTimeout(time.Minute)(rc)
+	err := rc.Query(ctx, RoundLatest, "test.Query", nil, nil)
+	require.NoError(err, "Query")
+	require.Equal("second", mc.requestID, "request ID should be forwarded with queries")
+}
+
+func TestRequestIDMetadata(t *testing.T) {
+	require := require.New(t)
+
+	// Serve all methods with a handler that records the incoming request metadata.
+	mdCh := make(chan metadata.MD, 1)
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		mdCh <- md
+		return status.Error(codes.Unimplemented, "not implemented")
+	}))
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	require.NoError(err, "Dial")
+	defer conn.Close()
+
+	var runtimeID common.Namespace
+	rc := New(conn, runtimeID)
+	ctx := WithRequestID(context.Background(), "test-request")
+	err = rc.Query(ctx, RoundLatest, "test.Query", nil, nil)
+	require.Error(err, "Query should fail against the test server")
+
+	md := <-mdCh
+	require.Equal([]string{"test-request"}, md.Get(RequestIDMetadataKey), "request ID should be sent as gRPC metadata")
+}
+
+type mockMetrics struct {
+	ops     []Operation
+	methods []string
+	errs    []error
+}
+
+func (mm *mockMetrics) Observe(op Operation, method string, duration time.Duration, err error) {
+	mm.ops = append(mm.ops, op)
+	mm.methods = append(mm.methods, method)
+	mm.errs = append(mm.errs, err)
+}
+
+func TestMetrics(t *testing.T) {
+	require := require.New(t)
+
+	mm := &mockMetrics{}
+	rc := &runtimeClient{cc: &mockCoreClient{}}
+	err := rc.Query(context.Background(), RoundLatest, "test.Query", nil, nil)
+	require.NoError(err, "Query")
+	require.Empty(mm.ops, "no metrics should be recorded by default")
+
+	WithMetrics(mm)(rc)
+	err = rc.Query(context.Background(), RoundLatest, "test.Query", nil, nil)
+	require.NoError(err, "Query")
+	require.Equal([]Operation{OperationQuery}, mm.ops)
+	require.Equal([]string{"test.Query"}, mm.methods)
+	require.Equal([]error{nil}, mm.errs)
+
+	require.Equal("accounts", MethodModule("accounts.Transfer"))
+	require.Equal("contracts", MethodModule("contracts"))
+
+	module, code := ErrorCode(fmt.Errorf("wrapped: %w", &types.FailedCallResult{Module: "accounts", Code: 2}))
+	require.Equal("accounts", module)
+	require.EqualValues(2, code)
+	module, code = ErrorCode(&CheckTxError{Module: "core", Code: 5})
+	require.Equal("core", module)
+	require.EqualValues(5, code)
+	module, code = ErrorCode(nil)
+	require.Empty(module)
+	require.EqualValues(0, code)
+}
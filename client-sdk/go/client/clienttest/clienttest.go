@@ -0,0 +1,492 @@
+// Package clienttest provides an in-memory runtime client for unit testing code built on top of
+// the runtime client (e.g., module helpers) without a running network.
+package clienttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	// ErrNotSupported is the error returned by methods that are not supported by the mock client.
+	ErrNotSupported = errors.New("clienttest: not supported by the mock runtime client")
+	// ErrNotFound is the error returned when looking up a block that has not been configured.
+	ErrNotFound = errors.New("clienttest: not found")
+)
+
+// QueryHandler handles a runtime query made at the given round with the given CBOR-encoded
+// arguments. The returned response is CBOR-encoded and decoded into the caller's response.
+type QueryHandler func(round uint64, args cbor.RawMessage) (interface{}, error)
+
+// SubmitHandler handles a submitted transaction and returns its call result.
+//
+// In case the handler returns a *client.CheckTxError, the transaction is treated as having failed
+// the transaction check and is reported as such in the transaction metadata.
+type SubmitHandler func(tx *types.UnverifiedTransaction) (*types.CallResult, error)
+
+var _ client.RuntimeClient = (*MockRuntimeClient)(nil)
+
+// MockRuntimeClient is an in-memory runtime client with programmable query responses, blocks,
+// transactions and events that records all submitted transactions.
+//
+// Each submitted transaction that passes the transaction check is considered to be executed in
+// its own round, starting at round 1. Submitted transactions are not added to the configured
+// blocks and transactions.
+//
+// RoundLatest refers to the highest round for which a block, transactions or events have been
+// configured. Watching blocks or events delivers all configured rounds in order and then closes
+// the channel.
+type MockRuntimeClient struct {
+	l sync.Mutex
+
+	info          types.RuntimeInfo
+	queries       map[string]QueryHandler
+	submitHandler SubmitHandler
+	submitted     []*types.UnverifiedTransaction
+	lastRound     uint64
+	blocks        map[uint64]*block.Block
+	txs           map[uint64][]*client.TransactionWithResults
+	events        map[uint64][]*types.Event
+}
+
+// NewMockRuntimeClient creates a new mock runtime client for a runtime with an all-zero
+// identifier.
+//
+// By default all queries fail and all submitted transactions succeed with an empty result.
+func NewMockRuntimeClient() *MockRuntimeClient {
+	var runtimeID common.Namespace
+	return &MockRuntimeClient{
+		info: types.RuntimeInfo{
+			ID:           runtimeID,
+			ChainContext: signature.DeriveChainContext(runtimeID, "clienttest"),
+		},
+		queries: make(map[string]QueryHandler),
+		submitHandler: func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+			return &types.CallResult{Ok: cbor.Marshal(nil)}, nil
+		},
+		blocks: make(map[uint64]*block.Block),
+		txs:    make(map[uint64][]*client.TransactionWithResults),
+		events: make(map[uint64][]*types.Event),
+	}
+}
+
+// SetRuntimeInfo sets the runtime information returned by GetInfo.
+func (mc *MockRuntimeClient) SetRuntimeInfo(info *types.RuntimeInfo) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	mc.info = *info
+}
+
+// SetQueryResponse configures the response for all queries of the given method.
+func (mc *MockRuntimeClient) SetQueryResponse(method string, rsp interface{}) {
+	mc.SetQueryHandler(method, func(uint64, cbor.RawMessage) (interface{}, error) {
+		return rsp, nil
+	})
+}
+
+// SetQueryHandler configures the handler for all queries of the given method.
+func (mc *MockRuntimeClient) SetQueryHandler(method string, handler QueryHandler) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	mc.queries[method] = handler
+}
+
+// SetSubmitHandler configures the handler for submitted transactions.
+func (mc *MockRuntimeClient) SetSubmitHandler(handler SubmitHandler) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	mc.submitHandler = handler
+}
+
+// SetBlock configures the block for the round given in its header.
+func (mc *MockRuntimeClient) SetBlock(blk *block.Block) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	mc.blocks[blk.Header.Round] = blk
+}
+
+// SetTransactions configures the transactions (together with their results and events) executed
+// in the given round. Transactions without a hash get the hash of the raw transaction.
+func (mc *MockRuntimeClient) SetTransactions(round uint64, txs []*client.TransactionWithResults) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	var emptyHash hash.Hash
+	for _, tx := range txs {
+		if tx.Hash.Equal(&emptyHash) {
+			tx.Hash = tx.Tx.Hash()
+		}
+	}
+	mc.txs[round] = txs
+}
+
+// SetEvents configures the events emitted in the given round.
+func (mc *MockRuntimeClient) SetEvents(round uint64, events []*types.Event) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	mc.events[round] = events
+}
+
+// SubmittedTransactions returns all transactions submitted so far, in order of submission.
+func (mc *MockRuntimeClient) SubmittedTransactions() []*types.UnverifiedTransaction {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	return append([]*types.UnverifiedTransaction{}, mc.submitted...)
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetInfo(ctx context.Context) (*types.RuntimeInfo, error) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	info := mc.info
+	return &info, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTxRaw(ctx context.Context, tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+	meta, err := mc.SubmitTxRawMeta(ctx, tx)
+	switch {
+	case err != nil:
+		return nil, err
+	case meta.CheckTxError != nil:
+		return nil, meta.CheckTxError
+	}
+	return &meta.Result, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTxRawMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*client.SubmitTxRawMeta, error) {
+	mc.l.Lock()
+	mc.submitted = append(mc.submitted, tx)
+	handler := mc.submitHandler
+	mc.l.Unlock()
+
+	result, err := handler(tx)
+	var checkTxErr *client.CheckTxError
+	switch {
+	case errors.As(err, &checkTxErr):
+		return &client.SubmitTxRawMeta{
+			TransactionMeta: client.TransactionMeta{CheckTxError: checkTxErr},
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	mc.l.Lock()
+	mc.lastRound++
+	round := mc.lastRound
+	mc.l.Unlock()
+
+	return &client.SubmitTxRawMeta{
+		TransactionMeta: client.TransactionMeta{Round: round},
+		Result:          *result,
+	}, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTx(ctx context.Context, tx *types.UnverifiedTransaction) (cbor.RawMessage, error) {
+	result, err := mc.SubmitTxRaw(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case result.IsUnknown():
+		return nil, fmt.Errorf("got unknown result, use SubmitTxRaw to retrieve")
+	case result.IsSuccess():
+		return result.Ok, nil
+	default:
+		return nil, result.Failed
+	}
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTxMeta(ctx context.Context, tx *types.UnverifiedTransaction) (*client.SubmitTxMeta, error) {
+	meta, err := mc.SubmitTxRawMeta(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if an error was encountered during transaction checks.
+	if meta.CheckTxError != nil {
+		return &client.SubmitTxMeta{TransactionMeta: meta.TransactionMeta}, nil
+	}
+
+	switch {
+	case meta.Result.IsUnknown():
+		return nil, fmt.Errorf("got unknown result, use SubmitTxRawMeta to retrieve")
+	case meta.Result.IsSuccess():
+		return &client.SubmitTxMeta{
+			Result:          meta.Result.Ok,
+			TransactionMeta: meta.TransactionMeta,
+		}, nil
+	default:
+		return &client.SubmitTxMeta{TransactionMeta: meta.TransactionMeta}, meta.Result.Failed
+	}
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTxMetaDecoded(ctx context.Context, tx *types.UnverifiedTransaction, rsp interface{}) (*client.TransactionMeta, error) {
+	meta, err := mc.SubmitTxMeta(ctx, tx)
+	switch {
+	case meta == nil:
+		return nil, err
+	case err != nil:
+		return &meta.TransactionMeta, err
+	case meta.CheckTxError != nil:
+		return &meta.TransactionMeta, nil
+	}
+
+	if rsp != nil {
+		if err = cbor.Unmarshal(meta.Result, rsp); err != nil {
+			return &meta.TransactionMeta, fmt.Errorf("failed to unmarshal call result: %w", err)
+		}
+	}
+	return &meta.TransactionMeta, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) SubmitTxNoWait(ctx context.Context, tx *types.UnverifiedTransaction) error {
+	_, err := mc.SubmitTxRawMeta(ctx, tx)
+	return err
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) WaitForTxResults(ctx context.Context, hashes []hash.Hash, fromRound uint64, timeout time.Duration) (map[hash.Hash]*client.TxResult, error) {
+	return nil, ErrNotSupported
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetGenesisBlock(ctx context.Context) (*block.Block, error) {
+	return mc.GetBlock(ctx, 0)
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	round = mc.resolveRoundLocked(round)
+	blk, ok := mc.blocks[round]
+	if !ok {
+		return nil, fmt.Errorf("%w: block %d", ErrNotFound, round)
+	}
+	return blk, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) RoundTimestamp(ctx context.Context, round uint64) (time.Time, error) {
+	blk, err := mc.GetBlock(ctx, round)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(blk.Header.Timestamp), 0), nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetLastRetainedBlock(ctx context.Context) (*block.Block, error) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	for _, round := range mc.roundsLocked() {
+		if blk, ok := mc.blocks[round]; ok {
+			return blk, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no blocks", ErrNotFound)
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetTransactions(ctx context.Context, round uint64) ([]*types.UnverifiedTransaction, error) {
+	txs, err := mc.GetTransactionsWithResults(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTxs := make([]*types.UnverifiedTransaction, 0, len(txs))
+	for _, tx := range txs {
+		rawTx := tx.Tx
+		rawTxs = append(rawTxs, &rawTx)
+	}
+	return rawTxs, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetTransactionsWithResults(ctx context.Context, round uint64) ([]*client.TransactionWithResults, error) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	return append([]*client.TransactionWithResults{}, mc.txs[mc.resolveRoundLocked(round)]...), nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetEventsRaw(ctx context.Context, round uint64) ([]*types.Event, error) {
+	mc.l.Lock()
+	defer mc.l.Unlock()
+
+	return append([]*types.Event{}, mc.events[mc.resolveRoundLocked(round)]...), nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) GetEvents(ctx context.Context, round uint64, decoders []client.EventDecoder, includeUndecoded bool) ([]client.DecodedEvent, error) {
+	rawEvs, err := mc.GetEventsRaw(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEvents(rawEvs, decoders, includeUndecoded)
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) WatchBlocks(ctx context.Context) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	mc.l.Lock()
+	var blks []*roothash.AnnotatedBlock
+	for _, round := range mc.roundsLocked() {
+		if blk, ok := mc.blocks[round]; ok {
+			blks = append(blks, &roothash.AnnotatedBlock{Height: int64(round), Block: blk})
+		}
+	}
+	mc.l.Unlock()
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *roothash.AnnotatedBlock)
+	go func() {
+		defer close(ch)
+
+		for _, blk := range blks {
+			select {
+			case ch <- blk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, sub, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) WatchEvents(ctx context.Context, decoders []client.EventDecoder, includeUndecoded bool) (<-chan *client.BlockEvents, error) {
+	mc.l.Lock()
+	var bevs []*client.BlockEvents
+	for _, round := range mc.roundsLocked() {
+		evs, err := decodeEvents(mc.events[round], decoders, includeUndecoded)
+		if err != nil {
+			mc.l.Unlock()
+			return nil, err
+		}
+		bevs = append(bevs, &client.BlockEvents{Round: round, Events: evs})
+	}
+	mc.l.Unlock()
+
+	ch := make(chan *client.BlockEvents)
+	go func() {
+		defer close(ch)
+
+		for _, bev := range bevs {
+			select {
+			case ch <- bev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Implements client.RuntimeClient.
+func (mc *MockRuntimeClient) Query(ctx context.Context, round uint64, method string, args, rsp interface{}) error {
+	mc.l.Lock()
+	handler, ok := mc.queries[method]
+	mc.l.Unlock()
+	if !ok {
+		return fmt.Errorf("clienttest: no response configured for query '%s'", method)
+	}
+
+	result, err := handler(round, cbor.Marshal(args))
+	if err != nil {
+		return err
+	}
+	if rsp != nil {
+		if err = cbor.Unmarshal(cbor.Marshal(result), rsp); err != nil {
+			return fmt.Errorf("failed to unmarshal query response: %w", err)
+		}
+	}
+	return nil
+}
+
+// roundsLocked returns all rounds for which a block, transactions or events have been configured
+// in ascending order.
+func (mc *MockRuntimeClient) roundsLocked() []uint64 {
+	seen := make(map[uint64]bool)
+	var rounds []uint64
+	add := func(round uint64) {
+		if !seen[round] {
+			seen[round] = true
+			rounds = append(rounds, round)
+		}
+	}
+	for round := range mc.blocks {
+		add(round)
+	}
+	for round := range mc.txs {
+		add(round)
+	}
+	for round := range mc.events {
+		add(round)
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i] < rounds[j] })
+	return rounds
+}
+
+// resolveRoundLocked resolves RoundLatest to the highest configured round.
+func (mc *MockRuntimeClient) resolveRoundLocked(round uint64) uint64 {
+	if round != client.RoundLatest {
+		return round
+	}
+	rounds := mc.roundsLocked()
+	if len(rounds) == 0 {
+		return 0
+	}
+	return rounds[len(rounds)-1]
+}
+
+func decodeEvents(rawEvs []*types.Event, decoders []client.EventDecoder, includeUndecoded bool) ([]client.DecodedEvent, error) {
+	evs := make([]client.DecodedEvent, 0)
+OUTER:
+	for _, ev := range rawEvs {
+		for _, decoder := range decoders {
+			decoded, err := decoder.DecodeEvent(ev)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode event: %w", err)
+			}
+			if decoded != nil {
+				evs = append(evs, decoded)
+				continue OUTER
+			}
+		}
+		if includeUndecoded {
+			evs = append(evs, ev)
+		}
+	}
+	return evs, nil
+}
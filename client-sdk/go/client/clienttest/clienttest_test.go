@@ -0,0 +1,213 @@
+package clienttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestMockQuery(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := NewMockRuntimeClient()
+	ac := accounts.NewV1(mc)
+
+	_, err := ac.Balances(ctx, 1, sdkTesting.Alice.Address)
+	require.Error(err, "queries without a configured response should fail")
+
+	mc.SetQueryHandler("accounts.Balances", func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		var q accounts.BalancesQuery
+		if uerr := cbor.Unmarshal(args, &q); uerr != nil {
+			return nil, uerr
+		}
+		require.EqualValues(42, round)
+		require.Equal(sdkTesting.Alice.Address, q.Address)
+		return &accounts.AccountBalances{
+			Balances: map[types.Denomination]types.Quantity{
+				types.NativeDenomination: *quantity.NewFromUint64(100),
+			},
+		}, nil
+	})
+	balances, err := ac.Balances(ctx, 42, sdkTesting.Alice.Address)
+	require.NoError(err, "Balances")
+	require.Equal("100", balances.Balances[types.NativeDenomination].String())
+
+	mc.SetQueryResponse("accounts.Addresses", accounts.Addresses{sdkTesting.Bob.Address})
+	addrs, err := ac.Addresses(ctx, 1, types.NativeDenomination)
+	require.NoError(err, "Addresses")
+	require.Equal(accounts.Addresses{sdkTesting.Bob.Address}, addrs)
+}
+
+func TestMockSubmit(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := NewMockRuntimeClient()
+	ac := accounts.NewV1(mc)
+
+	tb := ac.Transfer(sdkTesting.Bob.Address, types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination)).
+		AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	require.NoError(tb.AppendSign(ctx, sdkTesting.Alice.Signer), "AppendSign")
+	meta, err := tb.SubmitTxMeta(ctx, nil)
+	require.NoError(err, "SubmitTxMeta")
+	require.EqualValues(1, meta.Round)
+
+	submitted := mc.SubmittedTransactions()
+	require.Len(submitted, 1)
+	rtInfo, err := mc.GetInfo(ctx)
+	require.NoError(err, "GetInfo")
+	tx, err := submitted[0].Verify(rtInfo.ChainContext)
+	require.NoError(err, "submitted transaction should be signed")
+	require.Equal("accounts.Transfer", tx.Call.Method)
+
+	mc.SetSubmitHandler(func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		return &types.CallResult{Failed: &types.FailedCallResult{Module: "accounts", Code: 2}}, nil
+	})
+	err = tb.SubmitTx(ctx, nil)
+	require.Error(err, "SubmitTx should return the failed call result")
+	require.Len(mc.SubmittedTransactions(), 2)
+
+	mc.SetSubmitHandler(func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		return nil, &client.CheckTxError{Module: "core", Code: 5, Message: "invalid nonce"}
+	})
+	meta, err = tb.SubmitTxMeta(ctx, nil)
+	require.NoError(err, "SubmitTxMeta should report check failures in the metadata")
+	require.NotNil(meta.CheckTxError)
+	require.EqualValues(5, meta.CheckTxError.Code)
+	require.EqualValues(0, meta.Round, "transactions failing the check should not be executed")
+	err = tb.SubmitTx(ctx, nil)
+	require.Error(err, "SubmitTx should return the check failure")
+	require.Len(mc.SubmittedTransactions(), 4)
+
+	mc.SetSubmitHandler(func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		return &types.CallResult{Ok: cbor.Marshal(nil)}, nil
+	})
+	meta, err = tb.SubmitTxMeta(ctx, nil)
+	require.NoError(err, "SubmitTxMeta")
+	require.EqualValues(3, meta.Round, "only executed transactions should advance the round")
+}
+
+func TestMockEvents(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := NewMockRuntimeClient()
+	ac := accounts.NewV1(mc)
+
+	transfer := &accounts.TransferEvent{
+		From:   sdkTesting.Alice.Address,
+		To:     sdkTesting.Bob.Address,
+		Amount: types.NewBaseUnits(*quantity.NewFromUint64(10), types.NativeDenomination),
+	}
+	mc.SetEvents(5, []*types.Event{
+		{Module: accounts.ModuleName, Code: accounts.TransferEventCode, Value: cbor.Marshal(transfer)},
+		{Module: "other", Code: 1},
+	})
+
+	evs, err := ac.GetEvents(ctx, 5)
+	require.NoError(err, "GetEvents")
+	require.Len(evs, 1)
+	require.Equal(transfer, evs[0].Transfer)
+
+	decoded, err := mc.GetEvents(ctx, 5, nil, true)
+	require.NoError(err, "GetEvents")
+	require.Len(decoded, 2, "undecoded events should be included")
+
+	evs, err = ac.GetEvents(ctx, 6)
+	require.NoError(err, "GetEvents")
+	require.Empty(evs)
+}
+
+func TestMockBlocks(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := NewMockRuntimeClient()
+
+	_, err := mc.GetBlock(ctx, client.RoundLatest)
+	require.ErrorIs(err, ErrNotFound, "GetBlock without configured blocks")
+
+	for round := uint64(3); round <= 5; round++ {
+		var blk block.Block
+		blk.Header.Round = round
+		blk.Header.Timestamp = block.Timestamp(1_000 + round)
+		mc.SetBlock(&blk)
+	}
+	tx := types.UnverifiedTransaction{Body: cbor.Marshal("tx")}
+	mc.SetTransactions(4, []*client.TransactionWithResults{
+		{Tx: tx, Result: types.CallResult{Ok: cbor.Marshal(nil)}},
+	})
+
+	blk, err := mc.GetBlock(ctx, client.RoundLatest)
+	require.NoError(err, "GetBlock")
+	require.EqualValues(5, blk.Header.Round, "RoundLatest should resolve to the highest round")
+	blk, err = mc.GetLastRetainedBlock(ctx)
+	require.NoError(err, "GetLastRetainedBlock")
+	require.EqualValues(3, blk.Header.Round)
+	ts, err := mc.RoundTimestamp(ctx, 4)
+	require.NoError(err, "RoundTimestamp")
+	require.EqualValues(1_004, ts.Unix())
+	_, err = mc.GetBlock(ctx, 6)
+	require.ErrorIs(err, ErrNotFound, "GetBlock for an unknown round")
+
+	txs, err := mc.GetTransactionsWithResults(ctx, 4)
+	require.NoError(err, "GetTransactionsWithResults")
+	require.Len(txs, 1)
+	require.Equal(tx.Hash(), txs[0].Hash, "missing hashes should be filled in")
+	rawTxs, err := mc.GetTransactions(ctx, 4)
+	require.NoError(err, "GetTransactions")
+	require.Equal([]*types.UnverifiedTransaction{&tx}, rawTxs)
+	rawTxs, err = mc.GetTransactions(ctx, client.RoundLatest)
+	require.NoError(err, "GetTransactions")
+	require.Empty(rawTxs)
+
+	blkCh, sub, err := mc.WatchBlocks(ctx)
+	require.NoError(err, "WatchBlocks")
+	defer sub.Close()
+	var rounds []uint64
+	for annBlk := range blkCh {
+		rounds = append(rounds, annBlk.Block.Header.Round)
+	}
+	require.Equal([]uint64{3, 4, 5}, rounds, "all blocks should be delivered in order")
+}
+
+func TestMockWatchEvents(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := NewMockRuntimeClient()
+	ac := accounts.NewV1(mc)
+
+	burn := &accounts.BurnEvent{Owner: sdkTesting.Alice.Address}
+	mc.SetEvents(7, []*types.Event{{Module: "other", Code: 1}})
+	mc.SetEvents(2, []*types.Event{
+		{Module: accounts.ModuleName, Code: accounts.BurnEventCode, Value: cbor.Marshal(burn)},
+	})
+
+	ch, err := mc.WatchEvents(ctx, []client.EventDecoder{ac}, false)
+	require.NoError(err, "WatchEvents")
+	var bevs []*client.BlockEvents
+	for bev := range ch {
+		bevs = append(bevs, bev)
+	}
+	require.Len(bevs, 2, "all configured rounds should be delivered")
+	require.EqualValues(2, bevs[0].Round)
+	require.Equal([]client.DecodedEvent{&accounts.Event{Burn: burn}}, bevs[0].Events)
+	require.EqualValues(7, bevs[1].Round)
+	require.Empty(bevs[1].Events, "undecoded events should be skipped")
+
+	evs, err := mc.GetEventsRaw(ctx, client.RoundLatest)
+	require.NoError(err, "GetEventsRaw")
+	require.Len(evs, 1, "RoundLatest should resolve to the highest round")
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+var (
+	eventDecodersLock sync.RWMutex
+	eventDecoders     = make(map[string]EventDecoder)
+)
+
+// RegisterEventDecoder registers an event decoder for events emitted by the given module.
+//
+// The decoder is also used for events emitted by any of the module's submodules (e.g., a decoder
+// registered for "contracts" also handles events emitted by "contracts.<...>" unless a more
+// specific decoder is registered). Standard modules register their decoders when imported.
+//
+// Registering multiple decoders for the same module name panics.
+func RegisterEventDecoder(moduleName string, decoder EventDecoder) {
+	eventDecodersLock.Lock()
+	defer eventDecodersLock.Unlock()
+
+	if _, exists := eventDecoders[moduleName]; exists {
+		panic(fmt.Sprintf("client: event decoder for module '%s' already registered", moduleName))
+	}
+	eventDecoders[moduleName] = decoder
+}
+
+// DecodeAnyEvent decodes the given event using the decoder registered for the module that
+// emitted it.
+//
+// In case no decoder is registered for the module or the event is not relevant to the decoder,
+// `nil, nil` is returned.
+func DecodeAnyEvent(event *types.Event) (DecodedEvent, error) {
+	eventDecodersLock.RLock()
+	decoder := lookupEventDecoder(event.Module)
+	eventDecodersLock.RUnlock()
+
+	if decoder == nil {
+		return nil, nil
+	}
+	return decoder.DecodeEvent(event)
+}
+
+// lookupEventDecoder returns the decoder for the most specific registered module name prefix.
+func lookupEventDecoder(module string) EventDecoder {
+	for {
+		if decoder, ok := eventDecoders[module]; ok {
+			return decoder
+		}
+		idx := strings.LastIndex(module, ".")
+		if idx == -1 {
+			return nil
+		}
+		module = module[:idx]
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// Operation is the kind of a runtime client operation recorded by Metrics.
+type Operation string
+
+const (
+	// OperationQuery is a runtime query.
+	OperationQuery Operation = "query"
+	// OperationSubmit is a transaction submission.
+	OperationSubmit Operation = "submit"
+)
+
+// Metrics is a hook for recording metrics about runtime client operations. By default no metrics
+// are recorded.
+//
+// Implementations must be safe for concurrent use. See the metrics/prometheus package for an
+// adapter exporting the metrics to Prometheus.
+type Metrics interface {
+	// Observe records a completed operation invoking the given method (e.g., "accounts.Balances").
+	// The method is empty in case it cannot be determined (e.g., for transactions that do not
+	// use the SDK transaction format). In case the operation failed, err is the failure reason.
+	Observe(op Operation, method string, duration time.Duration, err error)
+}
+
+// WithMetrics configures the client to record metrics about queries and transaction submissions
+// using the given hook.
+//
+// Failed calls and failed transaction checks are recorded as errors.
+func WithMetrics(metrics Metrics) Option {
+	return func(rc *runtimeClient) {
+		rc.metrics = metrics
+	}
+}
+
+// MethodModule returns the name of the module handling the given method.
+func MethodModule(method string) string {
+	return strings.SplitN(method, ".", 2)[0]
+}
+
+// ErrorCode returns the module and code of the given error returned by a runtime client
+// operation. In case the error is nil, an empty module name and a zero code are returned.
+func ErrorCode(err error) (string, uint32) {
+	var failed *types.FailedCallResult
+	var checkTxErr *CheckTxError
+	switch {
+	case err == nil:
+		return "", 0
+	case errors.As(err, &failed):
+		return failed.Module, failed.Code
+	case errors.As(err, &checkTxErr):
+		return checkTxErr.Module, checkTxErr.Code
+	default:
+		return coreErrors.Code(err)
+	}
+}
+
+// observe records a completed operation started at the given time.
+func (rc *runtimeClient) observe(op Operation, method string, start time.Time, err error) {
+	if rc.metrics == nil {
+		return
+	}
+	rc.metrics.Observe(op, method, time.Since(start), err)
+}
+
+// observeSubmit records a completed submission of the given transaction started at the given time.
+func (rc *runtimeClient) observeSubmit(tx *types.UnverifiedTransaction, start time.Time, err error) {
+	if rc.metrics == nil {
+		return
+	}
+
+	// The transaction may not use the SDK transaction format (e.g., Ethereum transactions).
+	var method string
+	var decoded types.Transaction
+	if cbor.Unmarshal(tx.Body, &decoded) == nil {
+		method = decoded.Call.Method
+	}
+	rc.metrics.Observe(OperationSubmit, method, time.Since(start), err)
+}
+
+// callResultError returns the failure of the given call result or nil in case the call did not
+// fail.
+func callResultError(result *types.CallResult) error {
+	if result.Failed == nil {
+		return nil
+	}
+	return result.Failed
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+)
+
+// reconnectInitialBackoff is the initial delay before retrying a failed operation when automatic
+// reconnection is enabled.
+const reconnectInitialBackoff = 100 * time.Millisecond
+
+// watchedBlock is a block received from a block subscription.
+type watchedBlock struct {
+	blk *roothash.AnnotatedBlock
+	// reconnected is true for the first block delivered after the subscription has been
+	// re-established.
+	reconnected bool
+}
+
+// retry calls fn until it succeeds or the context is canceled. In case automatic reconnection is
+// not enabled, fn is called exactly once.
+func (rc *runtimeClient) retry(ctx context.Context, fn func() error) error {
+	err := fn()
+	if err == nil || rc.maxReconnectBackoff == 0 {
+		return err
+	}
+
+	delay := reconnectInitialBackoff
+	for {
+		if delay > rc.maxReconnectBackoff {
+			delay = rc.maxReconnectBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if err = fn(); err == nil {
+			return nil
+		}
+		delay *= 2
+	}
+}
+
+// watchBlocks subscribes to runtime blocks. In case automatic reconnection is enabled, the
+// subscription is transparently re-established when the underlying stream terminates and any
+// blocks missed in the meantime are fetched and delivered in order.
+//
+// The returned channel is closed when the context is canceled or, in case automatic reconnection
+// is not enabled, when the underlying stream terminates.
+func (rc *runtimeClient) watchBlocks(ctx context.Context) (<-chan *watchedBlock, error) {
+	blkCh, blkSub, err := rc.cc.WatchBlocks(ctx, rc.runtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *watchedBlock)
+	go func() {
+		defer close(ch)
+		defer func() {
+			blkSub.Close()
+		}()
+
+		var (
+			lastRound   uint64
+			haveLast    bool
+			reconnected bool
+		)
+		deliver := func(blk *roothash.AnnotatedBlock) bool {
+			select {
+			case ch <- &watchedBlock{blk: blk, reconnected: reconnected}:
+				reconnected = false
+				lastRound = blk.Block.Header.Round
+				haveLast = true
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			var (
+				blk *roothash.AnnotatedBlock
+				ok  bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case blk, ok = <-blkCh:
+			}
+
+			if !ok {
+				if rc.maxReconnectBackoff == 0 {
+					return
+				}
+
+				// The stream has terminated, re-establish the subscription.
+				blkSub.Close()
+				var (
+					newCh  <-chan *roothash.AnnotatedBlock
+					newSub pubsub.ClosableSubscription
+				)
+				if rerr := rc.retry(ctx, func() (werr error) {
+					newCh, newSub, werr = rc.cc.WatchBlocks(ctx, rc.runtimeID)
+					return
+				}); rerr != nil {
+					return
+				}
+				blkCh, blkSub = newCh, newSub
+				reconnected = true
+				continue
+			}
+
+			round := blk.Block.Header.Round
+			if haveLast && round <= lastRound {
+				// Skip blocks that have already been delivered before reconnecting.
+				continue
+			}
+			if reconnected && haveLast {
+				// Fetch any blocks that were missed while disconnected. Consensus height is not
+				// known for these blocks.
+				for missedRound := lastRound + 1; missedRound < round; missedRound++ {
+					var missed *roothash.AnnotatedBlock
+					rerr := rc.retry(ctx, func() error {
+						b, gerr := rc.GetBlock(ctx, missedRound)
+						if gerr != nil {
+							return gerr
+						}
+						missed = &roothash.AnnotatedBlock{Block: b}
+						return nil
+					})
+					if rerr != nil || !deliver(missed) {
+						return
+					}
+				}
+			}
+			if !deliver(blk) {
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common"
+	"github.com/oasisprotocol/oasis-core/go/common/pubsub"
+	roothash "github.com/oasisprotocol/oasis-core/go/roothash/api"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+	coreClient "github.com/oasisprotocol/oasis-core/go/runtime/client/api"
+)
+
+// mockReconnectClient is a core runtime client whose block subscriptions terminate after
+// delivering a fixed set of rounds.
+type mockReconnectClient struct {
+	coreClient.RuntimeClient
+
+	sync.Mutex
+
+	// streams are the rounds delivered by successive WatchBlocks calls. A nil entry causes the
+	// corresponding call to fail. All but the last stream are closed after delivering their rounds.
+	streams [][]uint64
+	// blockCalls is the number of GetBlock calls for each round.
+	blockCalls map[uint64]int
+}
+
+func newMockReconnectClient(streams ...[]uint64) *mockReconnectClient {
+	return &mockReconnectClient{
+		streams:    streams,
+		blockCalls: make(map[uint64]int),
+	}
+}
+
+func (mc *mockReconnectClient) WatchBlocks(ctx context.Context, runtimeID common.Namespace) (<-chan *roothash.AnnotatedBlock, pubsub.ClosableSubscription, error) {
+	mc.Lock()
+	defer mc.Unlock()
+
+	if len(mc.streams) == 0 {
+		return nil, nil, fmt.Errorf("no more streams")
+	}
+	rounds := mc.streams[0]
+	mc.streams = mc.streams[1:]
+	if rounds == nil {
+		return nil, nil, fmt.Errorf("connection refused")
+	}
+
+	ch := make(chan *roothash.AnnotatedBlock, len(rounds))
+	for _, round := range rounds {
+		var blk block.Block
+		blk.Header.Round = round
+		ch <- &roothash.AnnotatedBlock{Height: int64(round), Block: &blk}
+	}
+	if len(mc.streams) > 0 {
+		close(ch)
+	}
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+func (mc *mockReconnectClient) GetBlock(ctx context.Context, request *coreClient.GetBlockRequest) (*block.Block, error) {
+	mc.Lock()
+	defer mc.Unlock()
+
+	mc.blockCalls[request.Round]++
+	var blk block.Block
+	blk.Header.Round = request.Round
+	return &blk, nil
+}
+
+func (mc *mockReconnectClient) GetEvents(ctx context.Context, request *coreClient.GetEventsRequest) ([]*coreClient.Event, error) {
+	return nil, nil
+}
+
+func (mc *mockReconnectClient) getBlockCalls() map[uint64]int {
+	mc.Lock()
+	defer mc.Unlock()
+
+	calls := make(map[uint64]int)
+	for round, n := range mc.blockCalls {
+		calls[round] = n
+	}
+	return calls
+}
+
+func TestWatchEventsReconnect(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first stream terminates after round 3, re-subscribing fails once and the second stream
+	// starts at round 6 (re-delivering round 3), so rounds 4 and 5 must be backfilled.
+	mc := newMockReconnectClient([]uint64{1, 2, 3}, nil, []uint64{3, 6, 7}, []uint64{8})
+	rc := &runtimeClient{cc: mc}
+	WithAutoReconnect(10 * time.Millisecond)(rc)
+
+	ch, err := rc.WatchEvents(ctx, nil, false)
+	require.NoError(err, "WatchEvents")
+
+	var (
+		rounds      []uint64
+		reconnected []uint64
+	)
+	for len(rounds) < 8 {
+		select {
+		case ev, ok := <-ch:
+			require.True(ok, "channel should not be closed")
+			rounds = append(rounds, ev.Round)
+			if ev.Reconnected {
+				reconnected = append(reconnected, ev.Round)
+			}
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for events", "received rounds: %v", rounds)
+		}
+	}
+	require.Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8}, rounds, "all rounds should be delivered exactly once and in order")
+	require.Equal([]uint64{4, 8}, reconnected, "the first block after each reconnect should be marked")
+	require.Equal(map[uint64]int{4: 1, 5: 1}, mc.getBlockCalls(), "missed blocks should be fetched exactly once")
+
+	select {
+	case ev := <-ch:
+		require.FailNow("unexpected event", "round: %d", ev.Round)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatchBlocksReconnect(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mc := newMockReconnectClient([]uint64{1, 2}, []uint64{2, 4}, nil, nil, []uint64{5})
+	rc := &runtimeClient{cc: mc}
+	WithAutoReconnect(10 * time.Millisecond)(rc)
+
+	ch, sub, err := rc.WatchBlocks(ctx)
+	require.NoError(err, "WatchBlocks")
+	defer sub.Close()
+
+	var (
+		rounds  []uint64
+		heights []int64
+	)
+	for len(rounds) < 5 {
+		select {
+		case blk, ok := <-ch:
+			require.True(ok, "channel should not be closed")
+			rounds = append(rounds, blk.Block.Header.Round)
+			heights = append(heights, blk.Height)
+		case <-time.After(5 * time.Second):
+			require.FailNow("timed out waiting for blocks", "received rounds: %v", rounds)
+		}
+	}
+	require.Equal([]uint64{1, 2, 3, 4, 5}, rounds, "all rounds should be delivered exactly once and in order")
+	require.Equal([]int64{1, 2, 0, 4, 5}, heights, "backfilled blocks should have no consensus height")
+	require.Equal(map[uint64]int{3: 1}, mc.getBlockCalls(), "missed blocks should be fetched exactly once")
+}
+
+func TestWatchEventsNoReconnect(t *testing.T) {
+	require := require.New(t)
+
+	mc := newMockReconnectClient([]uint64{1, 2, 3}, []uint64{4})
+	rc := &runtimeClient{cc: mc}
+
+	ch, err := rc.WatchEvents(context.Background(), nil, false)
+	require.NoError(err, "WatchEvents")
+
+	var rounds []uint64
+	for ev := range ch {
+		require.False(ev.Reconnected, "blocks should not be marked as reconnected")
+		rounds = append(rounds, ev.Round)
+	}
+	require.Equal([]uint64{1, 2, 3}, rounds, "channel should be closed when the stream terminates")
+	require.Empty(mc.getBlockCalls(), "no blocks should be fetched")
+}
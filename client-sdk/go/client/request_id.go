@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDMetadataKey is the gRPC metadata key under which the request identifier attached via
+// WithRequestID is sent to the node.
+const RequestIDMetadataKey = "x-request-id"
+
+// WithRequestID returns a derived context carrying the given request identifier. All queries and
+// transaction submissions made with the returned context forward the identifier to the node as
+// gRPC metadata (under RequestIDMetadataKey) so that client activity can be correlated with node
+// logs.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(RequestIDMetadataKey, id)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// RequestIDFromContext returns the request identifier attached to the given context via
+// WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	ids := md.Get(RequestIDMetadataKey)
+	if len(ids) == 0 {
+		return "", false
+	}
+	return ids[len(ids)-1], true
+}
@@ -5,40 +5,145 @@ import (
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
+// maxAmountBits is the maximum size of amounts accepted by the runtime in bits.
+const maxAmountBits = 128
+
+// BodyValidator is implemented by call bodies that can check themselves for well-formedness
+// before the transaction is signed and submitted.
+type BodyValidator interface {
+	// Validate returns an error in case the call body is malformed.
+	Validate() error
+}
+
 // TransactionBuilder is a helper for building and submitting transactions.
+//
+// A TransactionBuilder is not safe for concurrent use.
 type TransactionBuilder struct {
-	rc RuntimeClient
-	tx *types.Transaction
-	ts *types.TransactionSigner
+	rc   RuntimeClient
+	tx   *types.Transaction
+	ts   *types.TransactionSigner
+	body interface{}
 
-	callMeta interface{}
+	feeAmount   types.Quantity
+	priorityFee types.Quantity
+	callMeta    interface{}
 }
 
 // NewTransactionBuilder creates a new transaction builder.
 func NewTransactionBuilder(rc RuntimeClient, method string, body interface{}) *TransactionBuilder {
 	return &TransactionBuilder{
-		rc: rc,
-		tx: types.NewTransaction(nil, method, body),
+		rc:   rc,
+		tx:   types.NewTransaction(nil, method, body),
+		body: body,
+	}
+}
+
+// Validate checks the call body for well-formedness in case it implements BodyValidator and
+// checks that the fee amount fits into the runtime's 128-bit amounts.
+//
+// Validate is called automatically by AppendSign before the first signature is appended, so a
+// malformed transaction can never be signed. GetTransaction does not validate the transaction as
+// it is also used to obtain partially built transactions (e.g., for gas estimation) and cannot
+// report errors.
+func (tb *TransactionBuilder) Validate() error {
+	if tb.tx.AuthInfo.Fee.Amount.Amount.ToBigInt().BitLen() > maxAmountBits {
+		return fmt.Errorf("fee amount overflow (amount: %s)", tb.tx.AuthInfo.Fee.Amount.Amount)
+	}
+	if v, ok := tb.body.(BodyValidator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("malformed call body: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateWith performs the same checks as Validate and additionally runs the given check on the
+// unsigned transaction (e.g. core.Parameters.CheckTransaction to check it against runtime limits).
+func (tb *TransactionBuilder) ValidateWith(check func(*types.Transaction) error) error {
+	if err := tb.Validate(); err != nil {
+		return err
 	}
+	return check(tb.tx)
 }
 
-// SetFeeAmount configures the fee amount to be paid by the caller.
+// SetFeeAmount configures the fee amount to be paid by the caller. Any priority fee configured
+// via SetPriorityFee is added to the given amount. Fee amounts that do not fit into the runtime's
+// 128-bit amounts are rejected by Validate.
 func (tb *TransactionBuilder) SetFeeAmount(amount types.BaseUnits) *TransactionBuilder {
-	tb.tx.AuthInfo.Fee.Amount = amount
+	tb.feeAmount = *amount.Amount.Clone()
+	tb.tx.AuthInfo.Fee.Amount.Denomination = amount.Denomination
+	tb.tx.AuthInfo.Fee.Amount.Amount = *tb.totalFeeAmount()
 	return tb
 }
 
+// SetFeeDenomination configures the denomination in which the fee is to be paid.
+//
+// Note that the runtime only accepts fees in denominations for which a minimum gas price has been
+// configured (see core.MinGasPrice).
+func (tb *TransactionBuilder) SetFeeDenomination(denomination types.Denomination) *TransactionBuilder {
+	tb.tx.AuthInfo.Fee.Amount.Denomination = denomination
+	return tb
+}
+
+// SetFeeGasPrice configures the fee amount based on the given gas price and the currently
+// configured maximum gas amount. The fee denomination is left unchanged and any priority fee
+// configured via SetPriorityFee is added to the computed amount.
+//
+// This method should be called after the gas amount has been configured via SetFeeGas. An error
+// is returned in case the resulting fee amount does not fit into the runtime's 128-bit amounts.
+func (tb *TransactionBuilder) SetFeeGasPrice(gasPrice types.Quantity) error {
+	amount := quantity.NewFromUint64(tb.tx.AuthInfo.Fee.Gas)
+	if err := amount.Mul(&gasPrice); err != nil {
+		return fmt.Errorf("failed to compute fee amount: %w", err)
+	}
+	total := amount.Clone()
+	if err := total.Add(&tb.priorityFee); err != nil {
+		return fmt.Errorf("failed to add priority fee: %w", err)
+	}
+	if total.ToBigInt().BitLen() > maxAmountBits {
+		return fmt.Errorf("fee amount overflow (gas: %d, gas price: %s)", tb.tx.AuthInfo.Fee.Gas, gasPrice)
+	}
+	tb.feeAmount = *amount
+	tb.tx.AuthInfo.Fee.Amount.Amount = *total
+	return nil
+}
+
 // SetFeeGas configures the maximum gas amount that can be used by the transaction.
 func (tb *TransactionBuilder) SetFeeGas(gas uint64) *TransactionBuilder {
 	tb.tx.AuthInfo.Fee.Gas = gas
 	return tb
 }
 
+// SetPriorityFee configures an additional fee amount (in the fee denomination) that is paid on
+// top of the configured fee amount in order to get the transaction included faster under
+// congestion.
+//
+// Runtimes prioritize transactions by their gas price (fee amount divided by the maximum gas
+// amount), so the priority fee raises the transaction's priority. The priority fee is immediately
+// included in the transaction's fee amount and is kept when the fee amount is changed via
+// SetFeeAmount or SetFeeGasPrice, regardless of the order of calls. Note that the priority fee is
+// charged even on runtimes which do not prioritize transactions. Fee amounts that do not fit into
+// the runtime's 128-bit amounts are rejected by Validate.
+func (tb *TransactionBuilder) SetPriorityFee(amount types.Quantity) *TransactionBuilder {
+	tb.priorityFee = *amount.Clone()
+	tb.tx.AuthInfo.Fee.Amount.Amount = *tb.totalFeeAmount()
+	return tb
+}
+
+// totalFeeAmount returns the configured fee amount including the priority fee.
+func (tb *TransactionBuilder) totalFeeAmount() *types.Quantity {
+	total := tb.feeAmount.Clone()
+	// Adding two non-negative quantities cannot fail.
+	_ = total.Add(&tb.priorityFee)
+	return total
+}
+
 // SetFeeConsensusMessages configures the maximum number of consensus messages that can be emitted
 // by the transaction.
 func (tb *TransactionBuilder) SetFeeConsensusMessages(consensusMessages uint32) *TransactionBuilder {
@@ -82,6 +187,63 @@ func (tb *TransactionBuilder) AppendAuthMultisig(config *types.MultisigConfig, n
 	return tb
 }
 
+// Signers returns the address specifications of all signers specified in the AuthInfo so far, in
+// the order in which they need to sign. Signature signers have the Signature field set while
+// multisig signers have the Multisig field set.
+func (tb *TransactionBuilder) Signers() []types.AddressSpec {
+	signers := make([]types.AddressSpec, 0, len(tb.tx.AuthInfo.SignerInfo))
+	for _, si := range tb.tx.AuthInfo.SignerInfo {
+		signers = append(signers, si.AddressSpec)
+	}
+	return signers
+}
+
+// Nonces returns the nonces of all signers specified in the AuthInfo so far, in the same order
+// as returned by Signers.
+func (tb *TransactionBuilder) Nonces() []uint64 {
+	nonces := make([]uint64, 0, len(tb.tx.AuthInfo.SignerInfo))
+	for _, si := range tb.tx.AuthInfo.SignerInfo {
+		nonces = append(nonces, si.Nonce)
+	}
+	return nonces
+}
+
+// SetNonce changes the nonce of the signer at the given index (in the order returned by Signers).
+//
+// This method can only be called before the transaction is signed.
+func (tb *TransactionBuilder) SetNonce(signerIndex int, nonce uint64) error {
+	if tb.ts != nil {
+		return fmt.Errorf("unable to change nonce of a signed transaction")
+	}
+	if signerIndex < 0 || signerIndex >= len(tb.tx.AuthInfo.SignerInfo) {
+		return fmt.Errorf("invalid signer index: %d", signerIndex)
+	}
+	tb.tx.AuthInfo.SignerInfo[signerIndex].Nonce = nonce
+	return nil
+}
+
+// Clone returns an unsigned copy of the transaction builder which can be modified independently
+// of the original, e.g. to resubmit a stuck transaction with a bumped fee (via SetFeeAmount or
+// SetFeeGasPrice) or to replace it by using the same nonce (via SetNonce).
+//
+// Any signatures already appended to the original are not copied and the clone needs to be
+// signed again.
+func (tb *TransactionBuilder) Clone() *TransactionBuilder {
+	tx := *tb.tx
+	tx.Call.Body = append(cbor.RawMessage{}, tb.tx.Call.Body...)
+	tx.AuthInfo.SignerInfo = append([]types.SignerInfo{}, tb.tx.AuthInfo.SignerInfo...)
+	tx.AuthInfo.Fee.Amount.Amount = *tb.tx.AuthInfo.Fee.Amount.Amount.Clone()
+
+	return &TransactionBuilder{
+		rc:          tb.rc,
+		tx:          &tx,
+		body:        tb.body,
+		feeAmount:   *tb.feeAmount.Clone(),
+		priorityFee: *tb.priorityFee.Clone(),
+		callMeta:    tb.callMeta,
+	}
+}
+
 // GetTransaction returns the underlying unsigned transaction.
 func (tb *TransactionBuilder) GetTransaction() *types.Transaction {
 	return tb.tx
@@ -89,9 +251,13 @@ func (tb *TransactionBuilder) GetTransaction() *types.Transaction {
 
 // AppendSign signs the transaction and appends the signature.
 //
-// The signer must be specified in the AuthInfo.
+// The signer must be specified in the AuthInfo. The transaction is validated before the first
+// signature is appended.
 func (tb *TransactionBuilder) AppendSign(ctx context.Context, signer signature.Signer) error {
 	if tb.ts == nil {
+		if err := tb.Validate(); err != nil {
+			return err
+		}
 		tb.ts = tb.tx.PrepareForSigning()
 	}
 	rtInfo, err := tb.rc.GetInfo(ctx)
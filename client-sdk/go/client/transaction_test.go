@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestTransactionBuilderSigners(t *testing.T) {
+	require := require.New(t)
+
+	tb := NewTransactionBuilder(nil, "test.Method", nil)
+	require.Empty(tb.Signers(), "there should be no signers initially")
+	require.Empty(tb.Nonces(), "there should be no nonces initially")
+
+	multisig := &types.MultisigConfig{
+		Signers: []types.MultisigSigner{
+			{PublicKey: types.PublicKey{PublicKey: sdkTesting.Alice.Signer.Public()}, Weight: 1},
+			{PublicKey: types.PublicKey{PublicKey: sdkTesting.Bob.Signer.Public()}, Weight: 1},
+		},
+		Threshold: 2,
+	}
+	tb.AppendAuthSignature(sdkTesting.Alice.SigSpec, 1).
+		AppendAuthMultisig(multisig, 2).
+		AppendAuthSignature(sdkTesting.Dave.SigSpec, 3)
+
+	signers := tb.Signers()
+	require.Len(signers, 3)
+	require.Equal(sdkTesting.Alice.SigSpec, *signers[0].Signature)
+	require.Equal(multisig, signers[1].Multisig)
+	require.Equal(sdkTesting.Dave.SigSpec, *signers[2].Signature)
+	require.Equal([]uint64{1, 2, 3}, tb.Nonces())
+}
+
+type testBody struct {
+	Valid bool `json:"valid"`
+}
+
+func (b *testBody) Validate() error {
+	if !b.Valid {
+		return fmt.Errorf("invalid body")
+	}
+	return nil
+}
+
+func TestTransactionBuilderValidate(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &runtimeClient{cs: &mockConsensus{}}
+	newBuilder := func(valid bool) *TransactionBuilder {
+		return NewTransactionBuilder(rc, "test.Method", &testBody{Valid: valid}).
+			AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	}
+
+	tb := newBuilder(false)
+	require.Error(tb.Validate(), "Validate should reject an invalid body")
+	err := tb.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.Error(err, "AppendSign should reject an invalid body")
+	err = tb.SubmitTx(ctx, nil)
+	require.Error(err, "transaction with an invalid body should remain unsigned")
+
+	err = tb.ValidateWith(func(*types.Transaction) error { return nil })
+	require.Error(err, "ValidateWith should reject an invalid body")
+
+	tb = newBuilder(true)
+	require.NoError(tb.Validate(), "Validate")
+	err = tb.ValidateWith(func(*types.Transaction) error { return fmt.Errorf("too large") })
+	require.Error(err, "ValidateWith should run the additional check")
+	err = tb.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign should accept a valid body")
+}
+
+func TestTransactionBuilderFeeGasPrice(t *testing.T) {
+	require := require.New(t)
+
+	tb := NewTransactionBuilder(nil, "test.Method", nil).
+		SetFeeDenomination("FOO").
+		SetFeeGas(1_000)
+	err := tb.SetFeeGasPrice(*quantity.NewFromUint64(5))
+	require.NoError(err, "SetFeeGasPrice")
+	fee := tb.GetTransaction().AuthInfo.Fee
+	require.EqualValues(5_000, fee.Amount.Amount.ToBigInt().Int64(), "fee amount should be gas times gas price")
+	require.Equal(types.Denomination("FOO"), fee.Amount.Denomination, "fee denomination should be kept")
+
+	var price types.Quantity
+	require.NoError(price.FromBigInt(new(big.Int).Lsh(big.NewInt(1), 127)))
+	tb.SetFeeGas(2)
+	err = tb.SetFeeGasPrice(price)
+	require.Error(err, "SetFeeGasPrice should fail on fee amount overflow")
+	require.EqualValues(5_000, tb.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64(), "fee amount should be unchanged on error")
+
+	tb.SetFeeGas(1)
+	err = tb.SetFeeGasPrice(price)
+	require.NoError(err, "SetFeeGasPrice with the maximum fee amount")
+}
+
+func TestTransactionBuilderPriorityFee(t *testing.T) {
+	require := require.New(t)
+
+	feeAmount := func(tb *TransactionBuilder) int64 {
+		return tb.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64()
+	}
+
+	rc := &runtimeClient{cs: &mockConsensus{}}
+	tb := NewTransactionBuilder(rc, "test.Method", nil).
+		SetPriorityFee(*quantity.NewFromUint64(50)).
+		SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)).
+		SetFeeGas(10).
+		AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	require.EqualValues(150, feeAmount(tb), "priority fee should be added to the fee amount")
+
+	err := tb.AppendSign(context.Background(), sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign")
+	require.EqualValues(150, feeAmount(tb), "signing should not change the fee amount")
+
+	// The priority fee should be kept independent of the order of calls.
+	tb = NewTransactionBuilder(rc, "test.Method", nil).
+		SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)).
+		SetPriorityFee(*quantity.NewFromUint64(50))
+	require.EqualValues(150, feeAmount(tb))
+	tb.SetPriorityFee(*quantity.NewFromUint64(20))
+	require.EqualValues(120, feeAmount(tb), "priority fee should be replaced")
+	tb.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(200), types.NativeDenomination))
+	require.EqualValues(220, feeAmount(tb), "priority fee should be kept when changing the fee amount")
+
+	// Combined with a gas price.
+	tb.SetFeeGas(10)
+	err = tb.SetFeeGasPrice(*quantity.NewFromUint64(3))
+	require.NoError(err, "SetFeeGasPrice")
+	require.EqualValues(50, feeAmount(tb), "priority fee should be added to the gas price based amount")
+	tb.SetPriorityFee(*quantity.NewFromUint64(0))
+	require.EqualValues(30, feeAmount(tb), "priority fee should be removable")
+
+	var price types.Quantity
+	require.NoError(price.FromBigInt(new(big.Int).Lsh(big.NewInt(1), 127)))
+	tb.SetFeeGas(1).SetPriorityFee(price)
+	err = tb.SetFeeGasPrice(price)
+	require.Error(err, "SetFeeGasPrice should fail in case the priority fee overflows the fee amount")
+
+	// Oversized fee amounts configured via SetFeeAmount and SetPriorityFee are rejected on signing.
+	tb = NewTransactionBuilder(rc, "test.Method", nil).
+		SetFeeAmount(types.NewBaseUnits(price, types.NativeDenomination)).
+		SetFeeGas(10).
+		AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	require.NoError(tb.Validate(), "Validate with the maximum fee amount")
+	tb.SetPriorityFee(price)
+	require.Error(tb.Validate(), "Validate should reject a fee amount overflow")
+	err = tb.AppendSign(context.Background(), sdkTesting.Alice.Signer)
+	require.Error(err, "AppendSign should reject a fee amount overflow")
+}
+
+func TestTransactionBuilderClone(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &runtimeClient{cs: &mockConsensus{}}
+	tb := NewTransactionBuilder(rc, "test.Method", nil).
+		SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(100), types.NativeDenomination)).
+		SetFeeGas(10).
+		AppendAuthSignature(sdkTesting.Alice.SigSpec, 5)
+	err := tb.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign")
+	err = tb.SetNonce(0, 6)
+	require.Error(err, "SetNonce should fail on a signed transaction")
+
+	// Speed up the transaction by bumping the fee.
+	clone := tb.Clone()
+	clone.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(200), types.NativeDenomination))
+	err = clone.SetNonce(1, 6)
+	require.Error(err, "SetNonce should fail with an invalid signer index")
+	err = clone.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign on clone")
+
+	require.EqualValues(100, tb.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64(), "original should be unchanged")
+	require.Equal(tb.Nonces(), clone.Nonces(), "clone should keep the nonce")
+
+	info, err := rc.GetInfo(ctx)
+	require.NoError(err, "GetInfo")
+	ut := tb.ts.UnverifiedTransaction()
+	utClone := clone.ts.UnverifiedTransaction()
+	require.NotEqual(ut.Hash(), utClone.Hash(), "clone should be a distinct transaction")
+	for _, u := range []*types.UnverifiedTransaction{ut, utClone} {
+		_, err = u.Verify(info.ChainContext)
+		require.NoError(err, "Verify")
+	}
+
+	// Bump the nonce and the priority fee of an unsigned clone.
+	clone = tb.SetPriorityFee(*quantity.NewFromUint64(50)).Clone()
+	err = clone.SetNonce(0, 6)
+	require.NoError(err, "SetNonce")
+	err = clone.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign on clone")
+	require.Equal([]uint64{6}, clone.Nonces())
+	require.EqualValues(150, clone.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64())
+}
+
+func TestTransactionBuilderCloneAfterFeeChange(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	rc := &runtimeClient{cs: &mockConsensus{}}
+	tb := NewTransactionBuilder(rc, "test.Method", nil).
+		SetPriorityFee(*quantity.NewFromUint64(50)).
+		AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+	err := tb.AppendSign(ctx, sdkTesting.Alice.Signer)
+	require.NoError(err, "AppendSign")
+
+	// Changing the fee after signing must neither break cloning nor lose the new amount.
+	for _, amount := range []uint64{10, 1_000} {
+		tb.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination))
+		clone := tb.Clone()
+		require.EqualValues(amount+50, clone.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64())
+		err = clone.AppendSign(ctx, sdkTesting.Alice.Signer)
+		require.NoError(err, "AppendSign on clone")
+		require.EqualValues(amount+50, clone.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64())
+	}
+
+	// Modifying the clone must not affect the original.
+	clone := tb.Clone()
+	clone.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(1), types.NativeDenomination))
+	clone.GetTransaction().Call.Body[0] ^= 0xff
+	require.NoError(clone.SetNonce(0, 1), "SetNonce")
+	require.EqualValues(1_050, tb.GetTransaction().AuthInfo.Fee.Amount.Amount.ToBigInt().Int64())
+	require.Equal(cbor.Marshal(nil), []byte(tb.GetTransaction().Call.Body))
+	require.Equal([]uint64{0}, tb.Nonces())
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// Implements RuntimeClient.
+func (rc *runtimeClient) WaitForTxResults(ctx context.Context, hashes []hash.Hash, fromRound uint64, timeout time.Duration) (map[hash.Hash]*TxResult, error) {
+	results := make(map[hash.Hash]*TxResult, len(hashes))
+	if len(hashes) == 0 {
+		return results, nil
+	}
+
+	var cancel context.CancelFunc
+	switch {
+	case timeout > 0:
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	default:
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	pending := make(map[hash.Hash]bool, len(hashes))
+	for _, h := range hashes {
+		pending[h] = true
+	}
+
+	// collect looks for pending transactions in the given round.
+	collect := func(round uint64) error {
+		var txs []*TransactionWithResults
+		if err := rc.retry(ctx, func() (gerr error) {
+			txs, gerr = rc.GetTransactionsWithResults(ctx, round)
+			return
+		}); err != nil {
+			return err
+		}
+
+		for i, tx := range txs {
+			if !pending[tx.Hash] {
+				continue
+			}
+			delete(pending, tx.Hash)
+			results[tx.Hash] = &TxResult{
+				TransactionMeta: TransactionMeta{
+					Round:      round,
+					BatchOrder: uint32(i),
+				},
+				Result: tx.Result,
+				Events: tx.Events,
+			}
+		}
+		return nil
+	}
+
+	// Subscribe before scanning past rounds so that no blocks are missed in between.
+	blkCh, err := rc.watchBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nextRound := fromRound
+	for wb := range blkCh {
+		round := wb.blk.Block.Header.Round
+		if nextRound != RoundLatest {
+			if round < nextRound {
+				continue
+			}
+			// Scan rounds finalized before the subscription delivered its first block.
+			for r := nextRound; r < round && len(pending) > 0 && err == nil; r++ {
+				err = collect(r)
+			}
+			if err != nil {
+				break
+			}
+		}
+		nextRound = round + 1
+
+		if err = collect(round); err != nil {
+			break
+		}
+		if len(pending) == 0 {
+			return results, nil
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		err = ctx.Err()
+	case err == nil:
+		err = fmt.Errorf("block subscription terminated")
+	}
+	return results, fmt.Errorf("failed to wait for %d transaction results: %w", len(pending), err)
+}
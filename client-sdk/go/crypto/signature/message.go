@@ -0,0 +1,44 @@
+package signature
+
+import "fmt"
+
+// MessageContextBase is the base domain separation context for application-level messages.
+//
+// It differs from the transaction signature context so that a message signature can never be
+// replayed as a transaction signature and vice versa.
+const MessageContextBase = "oasis-runtime-sdk/msg: v0"
+
+// messageContextMaxSize is the maximum size of the full message signing context.
+const messageContextMaxSize = 255
+
+// MessageContext returns the full domain separation context used for signing application-level
+// messages under the given application context.
+func MessageContext(context string) ([]byte, error) {
+	if context == "" {
+		return nil, fmt.Errorf("signature: empty message context")
+	}
+	ctx := []byte(MessageContextBase + ": " + context)
+	if len(ctx) > messageContextMaxSize {
+		return nil, fmt.Errorf("signature: message context too long")
+	}
+	return ctx, nil
+}
+
+// SignMessage signs an application-level message under the given application context.
+func SignMessage(signer Signer, context string, message []byte) ([]byte, error) {
+	ctx, err := MessageContext(context)
+	if err != nil {
+		return nil, err
+	}
+	return signer.ContextSign(ctx, message)
+}
+
+// VerifyMessage returns true iff the signature is a valid signature of the application-level
+// message under the given application context.
+func VerifyMessage(pk PublicKey, context string, message, signature []byte) bool {
+	ctx, err := MessageContext(context)
+	if err != nil {
+		return false
+	}
+	return pk.Verify(ctx, message, signature)
+}
@@ -0,0 +1,40 @@
+package signature_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestSignMessage(t *testing.T) {
+	require := require.New(t)
+
+	message := []byte("hello world")
+	txCtx := signature.Context("test chain").New(types.SignatureContextBase)
+
+	for _, key := range []sdkTesting.TestKey{sdkTesting.Alice, sdkTesting.Dave} {
+		signer := key.Signer
+		pk := signer.Public()
+
+		sig, err := signature.SignMessage(signer, "test-app", message)
+		require.NoError(err, "SignMessage")
+		require.True(signature.VerifyMessage(pk, "test-app", message, sig), "message signature should verify")
+		require.False(signature.VerifyMessage(pk, "other-app", message, sig), "message signature should not verify under another context")
+		require.False(signature.VerifyMessage(pk, "test-app", []byte("other"), sig), "message signature should not verify for another message")
+		require.False(pk.Verify(txCtx, message, sig), "message signature should not verify under a transaction context")
+
+		txSig, err := signer.ContextSign(txCtx, message)
+		require.NoError(err, "ContextSign")
+		require.False(signature.VerifyMessage(pk, "test-app", message, txSig), "transaction signature should not verify as a message signature")
+	}
+
+	_, err := signature.SignMessage(sdkTesting.Alice.Signer, "", message)
+	require.Error(err, "SignMessage should fail with an empty context")
+	_, err = signature.SignMessage(sdkTesting.Alice.Signer, strings.Repeat("x", 256), message)
+	require.Error(err, "SignMessage should fail with a too long context")
+}
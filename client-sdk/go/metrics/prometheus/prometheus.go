@@ -0,0 +1,82 @@
+// Package prometheus provides a runtime client metrics hook exporting metrics to Prometheus.
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// unknownLabel is the label value used in case the module or method is not known.
+const unknownLabel = "unknown"
+
+// Metrics is a runtime client metrics hook exporting metrics to Prometheus.
+//
+// The following metrics are exported (each prefixed by the configured namespace):
+//
+//   - oasis_sdk_client_operations_total{operation, module, method, error_module, error_code}
+//   - oasis_sdk_client_operation_duration_seconds{operation, module, method}
+type Metrics struct {
+	operations *prometheus.CounterVec
+	durations  *prometheus.HistogramVec
+}
+
+// Implements client.Metrics.
+func (m *Metrics) Observe(op client.Operation, method string, duration time.Duration, err error) {
+	module := unknownLabel
+	if method != "" {
+		module = client.MethodModule(method)
+	} else {
+		method = unknownLabel
+	}
+
+	var errModule, errCode string
+	if err != nil {
+		var code uint32
+		errModule, code = client.ErrorCode(err)
+		errCode = fmt.Sprintf("%d", code)
+	}
+
+	m.operations.WithLabelValues(string(op), module, method, errModule, errCode).Inc()
+	m.durations.WithLabelValues(string(op), module, method).Observe(duration.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.operations.Describe(ch)
+	m.durations.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.operations.Collect(ch)
+	m.durations.Collect(ch)
+}
+
+// New creates a new Prometheus metrics hook with metric names prefixed by the given namespace
+// (which may be empty). The returned hook needs to be registered with a Prometheus registry
+// (e.g., prometheus.MustRegister) and passed to the runtime client via client.WithMetrics.
+func New(namespace string) *Metrics {
+	return &Metrics{
+		operations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "oasis_sdk_client_operations_total",
+				Help:      "Number of runtime client operations. Error labels are empty for successful operations.",
+			},
+			[]string{"operation", "module", "method", "error_module", "error_code"},
+		),
+		durations: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "oasis_sdk_client_operation_duration_seconds",
+				Help:      "Duration of runtime client operations.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"operation", "module", "method"},
+		),
+	}
+}
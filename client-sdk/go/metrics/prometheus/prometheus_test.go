@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m := New("test")
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(reg.Register(m), "Register")
+
+	m.Observe(client.OperationQuery, "accounts.Balances", time.Millisecond, nil)
+	m.Observe(client.OperationQuery, "accounts.Balances", time.Millisecond, nil)
+	m.Observe(client.OperationSubmit, "accounts.Transfer", time.Second, fmt.Errorf("wrapped: %w", &types.FailedCallResult{Module: "accounts", Code: 2}))
+	m.Observe(client.OperationSubmit, "", time.Second, nil)
+
+	require.EqualValues(2, testutil.ToFloat64(m.operations.WithLabelValues("query", "accounts", "accounts.Balances", "", "")))
+	require.EqualValues(1, testutil.ToFloat64(m.operations.WithLabelValues("submit", "accounts", "accounts.Transfer", "accounts", "2")))
+	require.EqualValues(1, testutil.ToFloat64(m.operations.WithLabelValues("submit", "unknown", "unknown", "", "")))
+	require.EqualValues(3, testutil.CollectAndCount(m.durations), "durations should be recorded per method")
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -28,20 +29,45 @@ type V1 interface {
 	// Transfer generates an accounts.Transfer transaction.
 	Transfer(to types.Address, amount types.BaseUnits) *client.TransactionBuilder
 
+	// MultiTransfer signs and submits an accounts.Transfer transaction for each of the given
+	// transfers, using consecutive nonces of the account identified by spec. Each transaction
+	// pays the given fee.
+	//
+	// Transactions are submitted sequentially and a failed or malformed transfer does not prevent
+	// the remaining ones from being submitted. An error is only returned in case submission could
+	// not proceed, together with the results of transfers submitted up to that point.
+	MultiTransfer(ctx context.Context, signer signature.Signer, spec types.SignatureAddressSpec, fee types.Fee, transfers []Transfer) ([]*TransferResult, error)
+
 	// Nonce queries the given account's nonce.
 	Nonce(ctx context.Context, round uint64, address types.Address) (uint64, error)
 
+	// NonceRange queries the range of nonces of the given account as of the latest round. It can
+	// be used to detect nonce gaps before submitting transactions with manually set nonces, as
+	// transactions following a gap cannot be executed until the gap is filled.
+	NonceRange(ctx context.Context, address types.Address) (*NonceRange, error)
+
 	// Balances queries the given account's balances.
 	Balances(ctx context.Context, round uint64, address types.Address) (*AccountBalances, error)
 
 	// Addresses queries all account addresses.
 	Addresses(ctx context.Context, round uint64, denomination types.Denomination) (Addresses, error)
 
+	// AllBalances returns an iterator over the balances of all accounts holding the given
+	// denomination. All balances are queried at the same round.
+	//
+	// The runtime does not support paginating the account list, so all addresses are fetched
+	// upfront while balances are fetched in batches as the iterator advances.
+	AllBalances(ctx context.Context, round uint64, denomination types.Denomination) (*BalancesIterator, error)
+
 	// DenominationInfo queries the information about a given denomination.
 	DenominationInfo(ctx context.Context, round uint64, denomination types.Denomination) (*DenominationInfo, error)
 
 	// GetEvents returns all account events emitted in a given block.
 	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
+
+	// WatchTransfers subscribes to transfer events where the given address is either the sender
+	// or the receiver. The returned channel is closed when the context is canceled.
+	WatchTransfers(ctx context.Context, address types.Address) (<-chan *TransferEvent, error)
 }
 
 type v1 struct {
@@ -56,6 +82,50 @@ func (a *v1) Transfer(to types.Address, amount types.BaseUnits) *client.Transact
 	})
 }
 
+// Implements V1.
+func (a *v1) MultiTransfer(ctx context.Context, signer signature.Signer, spec types.SignatureAddressSpec, fee types.Fee, transfers []Transfer) ([]*TransferResult, error) {
+	nonce, err := a.Nonce(ctx, client.RoundLatest, types.NewAddress(spec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nonce: %w", err)
+	}
+
+	results := make([]*TransferResult, 0, len(transfers))
+	for _, t := range transfers {
+		tb := a.Transfer(t.To, t.Amount).
+			SetFeeAmount(fee.Amount).
+			SetFeeGas(fee.Gas).
+			SetFeeConsensusMessages(fee.ConsensusMessages).
+			AppendAuthSignature(spec, nonce)
+
+		result := &TransferResult{Transfer: t}
+		if verr := tb.Validate(); verr != nil {
+			// Malformed transfers are never submitted so their nonce is not used.
+			result.Error = verr
+			results = append(results, result)
+			continue
+		}
+		if err = tb.AppendSign(ctx, signer); err != nil {
+			return results, fmt.Errorf("failed to sign transfer to %s: %w", t.To, err)
+		}
+
+		meta, serr := tb.SubmitTxMeta(ctx, nil)
+		switch {
+		case meta == nil:
+			// Submission failed, the outcome of the transaction is unknown.
+			return results, fmt.Errorf("failed to submit transfer to %s: %w", t.To, serr)
+		case meta.CheckTxError != nil:
+			// The transaction has not been included so its nonce has not been used.
+			result.Error = meta.CheckTxError
+		default:
+			result.Round = meta.Round
+			result.Error = serr
+			nonce++
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // Implements V1.
 func (a *v1) Nonce(ctx context.Context, round uint64, address types.Address) (uint64, error) {
 	var nonce uint64
@@ -66,6 +136,16 @@ func (a *v1) Nonce(ctx context.Context, round uint64, address types.Address) (ui
 	return nonce, nil
 }
 
+// Implements V1.
+func (a *v1) NonceRange(ctx context.Context, address types.Address) (*NonceRange, error) {
+	nonce, err := a.Nonce(ctx, client.RoundLatest, address)
+	if err != nil {
+		return nil, err
+	}
+	// The runtime client does not expose the transaction pool, so pending nonces are unknown.
+	return &NonceRange{Committed: nonce}, nil
+}
+
 // Implements V1.
 func (a *v1) Balances(ctx context.Context, round uint64, address types.Address) (*AccountBalances, error) {
 	var balances AccountBalances
@@ -86,6 +166,28 @@ func (a *v1) Addresses(ctx context.Context, round uint64, denomination types.Den
 	return addresses, nil
 }
 
+// Implements V1.
+func (a *v1) AllBalances(ctx context.Context, round uint64, denomination types.Denomination) (*BalancesIterator, error) {
+	if round == client.RoundLatest {
+		// Pin the round so that all balances are consistent.
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		round = blk.Header.Round
+	}
+
+	addresses, err := a.Addresses(ctx, round, denomination)
+	if err != nil {
+		return nil, err
+	}
+	return &BalancesIterator{
+		a:         a,
+		round:     round,
+		addresses: addresses,
+	}, nil
+}
+
 // Implements V1.
 func (a *v1) DenominationInfo(ctx context.Context, round uint64, denomination types.Denomination) (*DenominationInfo, error) {
 	var info DenominationInfo
@@ -118,6 +220,48 @@ func (a *v1) GetEvents(ctx context.Context, round uint64) ([]*Event, error) {
 	return evs, nil
 }
 
+// Implements V1.
+func (a *v1) WatchTransfers(ctx context.Context, address types.Address) (<-chan *TransferEvent, error) {
+	evCh, err := a.rc.WatchEvents(ctx, []client.EventDecoder{a}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *TransferEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var (
+				bev *client.BlockEvents
+				ok  bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case bev, ok = <-evCh:
+			}
+			if !ok {
+				return
+			}
+
+			for _, ev := range bev.Events {
+				transfer := ev.(*Event).Transfer
+				if transfer == nil || (!transfer.From.Equal(address) && !transfer.To.Equal(address)) {
+					continue
+				}
+				select {
+				case ch <- transfer:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
 // Implements client.EventDecoder.
 func (a *v1) DecodeEvent(event *types.Event) (client.DecodedEvent, error) {
 	if event.Module != ModuleName {
@@ -158,6 +302,10 @@ func NewV1(rc client.RuntimeClient) V1 {
 	return &v1{rc: rc}
 }
 
+func init() {
+	client.RegisterEventDecoder(ModuleName, &v1{})
+}
+
 // NewTransferTx generates a new accounts.Transfer transaction.
 func NewTransferTx(fee *types.Fee, body *Transfer) *types.Transaction {
 	return types.NewTransaction(fee, methodTransfer, body)
@@ -0,0 +1,153 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestWatchTransfers(t *testing.T) {
+	require := require.New(t)
+
+	alice, bob, charlie := sdkTesting.Alice.Address, sdkTesting.Bob.Address, sdkTesting.Charlie.Address
+	transfer := func(from, to types.Address, amount uint64) *types.Event {
+		return &types.Event{
+			Module: ModuleName,
+			Code:   TransferEventCode,
+			Value: cbor.Marshal(&TransferEvent{
+				From:   from,
+				To:     to,
+				Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination),
+			}),
+		}
+	}
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetEvents(1, []*types.Event{transfer(alice, bob, 1), transfer(bob, charlie, 2)})
+	mc.SetEvents(2, []*types.Event{{Module: ModuleName, Code: BurnEventCode, Value: cbor.Marshal(&BurnEvent{Owner: alice})}})
+	mc.SetEvents(3, []*types.Event{transfer(charlie, alice, 3)})
+
+	ch, err := NewV1(mc).WatchTransfers(context.Background(), alice)
+	require.NoError(err, "WatchTransfers")
+
+	var amounts []uint64
+	for ev := range ch {
+		require.True(ev.From.Equal(alice) || ev.To.Equal(alice), "only transfers involving the address should be delivered")
+		amounts = append(amounts, ev.Amount.Amount.ToBigInt().Uint64())
+	}
+	require.Equal([]uint64{1, 3}, amounts)
+}
+
+func TestNonceRange(t *testing.T) {
+	require := require.New(t)
+
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryResponse(methodNonce, uint64(5))
+	nr, err := NewV1(mc).NonceRange(context.Background(), sdkTesting.Alice.Address)
+	require.NoError(err, "NonceRange")
+	require.EqualValues(5, nr.Committed)
+	require.Nil(nr.HighestPending)
+	require.EqualValues(5, nr.Next())
+
+	pending := uint64(7)
+	for _, tc := range []struct {
+		nr    NonceRange
+		nonce uint64
+		gap   uint64
+	}{
+		{NonceRange{Committed: 5}, 3, 0},
+		{NonceRange{Committed: 5}, 5, 0},
+		{NonceRange{Committed: 5}, 8, 3},
+		{NonceRange{Committed: 5, HighestPending: &pending}, 8, 0},
+		{NonceRange{Committed: 5, HighestPending: &pending}, 10, 2},
+		{NonceRange{Committed: 9, HighestPending: &pending}, 10, 1},
+	} {
+		require.Equal(tc.gap, tc.nr.Gap(tc.nonce), "Gap(%d)", tc.nonce)
+	}
+}
+
+func TestMultiTransfer(t *testing.T) {
+	require := require.New(t)
+
+	// The outcome of each transfer is selected by its amount.
+	const (
+		amountOk = iota + 1
+		amountCheckTxError
+		amountFailed
+		amountSubmitError
+	)
+	failed := &types.FailedCallResult{Module: ModuleName, Code: 5, Message: "insufficient balance"}
+	checkTxErr := &client.CheckTxError{Module: "core", Code: 24, Message: "out of gas"}
+
+	var nonces []uint64
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryResponse(methodNonce, uint64(10))
+	mc.SetSubmitHandler(func(utx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		var tx types.Transaction
+		if err := cbor.Unmarshal(utx.Body, &tx); err != nil {
+			return nil, err
+		}
+		var body Transfer
+		if err := cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+			return nil, err
+		}
+		nonces = append(nonces, tx.AuthInfo.SignerInfo[0].Nonce)
+
+		switch body.Amount.Amount.ToBigInt().Uint64() {
+		case amountOk:
+			return &types.CallResult{Ok: cbor.Marshal(nil)}, nil
+		case amountCheckTxError:
+			return nil, checkTxErr
+		case amountFailed:
+			return &types.CallResult{Failed: failed}, nil
+		default:
+			return nil, fmt.Errorf("connection refused")
+		}
+	})
+
+	bob := sdkTesting.Bob.Address
+	transfer := func(to types.Address, amount uint64) Transfer {
+		return Transfer{To: to, Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination)}
+	}
+	transfers := []Transfer{
+		transfer(bob, amountOk),
+		transfer(bob, amountCheckTxError),
+		transfer(types.Address{}, amountOk),
+		transfer(bob, amountFailed),
+		transfer(bob, amountOk),
+		transfer(bob, amountSubmitError),
+		transfer(bob, amountOk),
+	}
+
+	fee := types.Fee{Amount: types.NewBaseUnits(*quantity.NewFromUint64(0), types.NativeDenomination), Gas: 1000}
+	results, err := NewV1(mc).MultiTransfer(context.Background(), sdkTesting.Alice.Signer, sdkTesting.Alice.SigSpec, fee, transfers)
+	require.Error(err, "MultiTransfer should abort when the outcome of a submission is unknown")
+	require.Equal([]uint64{10, 11, 11, 12, 13}, nonces, "nonce should only advance for executed transfers")
+
+	require.Len(results, 5, "results up to the aborted submission should be returned")
+	for i, r := range results {
+		require.Equal(transfers[i], r.Transfer)
+	}
+	require.NoError(results[0].Error)
+	require.EqualValues(1, results[0].Round)
+	require.Equal(checkTxErr, results[1].Error)
+	require.EqualValues(0, results[1].Round)
+	require.Error(results[2].Error, "malformed transfer should report a validation error")
+	require.EqualValues(0, results[2].Round)
+	var fcr *types.FailedCallResult
+	require.True(errors.As(results[3].Error, &fcr), "failed transfer should report the call result")
+	require.Equal(failed, fcr)
+	require.EqualValues(2, results[3].Round)
+	require.NoError(results[4].Error)
+	require.EqualValues(3, results[4].Round)
+}
@@ -0,0 +1,88 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// balancesBatchSize is the number of account balances fetched concurrently by BalancesIterator.
+const balancesBatchSize = 32
+
+// BalancesIterator iterates over the balances of all accounts holding a given denomination.
+//
+// Balances are fetched lazily in batches as the iterator advances.
+type BalancesIterator struct {
+	a         *v1
+	round     uint64
+	addresses Addresses
+
+	pos        int
+	batchStart int
+	batch      []*AccountBalances
+	err        error
+}
+
+// Total returns the total number of accounts the iterator will visit.
+func (it *BalancesIterator) Total() int {
+	return len(it.addresses)
+}
+
+// Next advances the iterator to the next account, fetching the next batch of balances if needed.
+// It returns false when the iteration is complete or an error occurred (see Err).
+func (it *BalancesIterator) Next(ctx context.Context) bool {
+	if it.err != nil || it.pos >= len(it.addresses) {
+		return false
+	}
+	idx := it.pos
+	it.pos++
+	if idx < it.batchStart+len(it.batch) {
+		return true
+	}
+
+	// Fetch the next batch.
+	it.batchStart = idx
+	end := it.batchStart + balancesBatchSize
+	if end > len(it.addresses) {
+		end = len(it.addresses)
+	}
+	addrs := it.addresses[it.batchStart:end]
+	batch := make([]*AccountBalances, len(addrs))
+	errs := make([]error, len(addrs))
+
+	var wg sync.WaitGroup
+	for i := range addrs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			batch[i], errs[i] = it.a.Balances(ctx, it.round, addrs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			it.err = fmt.Errorf("failed to query balances of %s: %w", addrs[i], err)
+			return false
+		}
+	}
+	it.batch = batch
+	return true
+}
+
+// Address returns the address of the current account.
+func (it *BalancesIterator) Address() types.Address {
+	return it.addresses[it.pos-1]
+}
+
+// Balances returns the balances of the current account.
+func (it *BalancesIterator) Balances() *AccountBalances {
+	return it.batch[it.pos-1-it.batchStart]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *BalancesIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,66 @@
+package accounts
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// newAccountsClient returns a mock runtime client serving the given accounts, where the native
+// balance of each account equals its index.
+func newAccountsClient(addresses Addresses) *clienttest.MockRuntimeClient {
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryResponse(methodAddresses, addresses)
+	mc.SetQueryHandler(methodBalances, func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		var q BalancesQuery
+		if err := cbor.Unmarshal(args, &q); err != nil {
+			return nil, err
+		}
+		for i, a := range addresses {
+			if a.Equal(q.Address) {
+				return &AccountBalances{
+					Balances: map[types.Denomination]types.Quantity{
+						types.NativeDenomination: *quantity.NewFromUint64(uint64(i)),
+					},
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("account not found: %s", q.Address)
+	})
+	return mc
+}
+
+func TestBalancesIterator(t *testing.T) {
+	require := require.New(t)
+
+	var addresses Addresses
+	for i := 0; i < 2*balancesBatchSize+3; i++ {
+		var id [8]byte
+		binary.BigEndian.PutUint64(id[:], uint64(i))
+		addresses = append(addresses, types.NewAddressForModule("test", id[:]))
+	}
+	mc := newAccountsClient(addresses)
+
+	it, err := NewV1(mc).AllBalances(context.Background(), 1, types.NativeDenomination)
+	require.NoError(err, "AllBalances")
+	require.Equal(len(addresses), it.Total())
+
+	var n int
+	for it.Next(context.Background()) {
+		require.True(addresses[n].Equal(it.Address()), "addresses should be visited in order")
+		balance := it.Balances().Balances[types.NativeDenomination]
+		require.EqualValues(n, balance.ToBigInt().Int64())
+		n++
+	}
+	require.NoError(it.Err(), "iteration should succeed")
+	require.Equal(len(addresses), n, "all accounts should be visited")
+}
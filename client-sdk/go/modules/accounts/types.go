@@ -1,6 +1,8 @@
 package accounts
 
 import (
+	"fmt"
+
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -10,11 +12,61 @@ type Transfer struct {
 	Amount types.BaseUnits `json:"amount"`
 }
 
+// TransferResult is the outcome of a single transfer submitted via MultiTransfer.
+type TransferResult struct {
+	Transfer
+
+	// Round is the round in which the transfer transaction was executed. It is zero in case the
+	// transaction was not executed.
+	Round uint64
+	// Error is nil in case the transfer succeeded. Otherwise it is either a
+	// *types.FailedCallResult when the transfer failed during execution or a *client.CheckTxError
+	// when the transaction failed the transaction check. Both carry the module and error code.
+	// Malformed transfers are not submitted and report the validation error instead.
+	Error error
+}
+
+// Validate implements client.BodyValidator.
+func (t *Transfer) Validate() error {
+	if t.To.Equal(types.Address{}) {
+		return fmt.Errorf("missing destination address")
+	}
+	return nil
+}
+
 // NonceQuery are the arguments for the accounts.Nonce query.
 type NonceQuery struct {
 	Address types.Address `json:"address"`
 }
 
+// NonceRange is the range of nonces of an account.
+type NonceRange struct {
+	// Committed is the account nonce as of the latest round, which is the nonce of the next
+	// transaction to be executed.
+	Committed uint64
+	// HighestPending is the highest nonce of the account's transactions pending execution or nil
+	// in case it is not known.
+	HighestPending *uint64
+}
+
+// Next returns the nonce that the next transaction of the account should use.
+func (nr *NonceRange) Next() uint64 {
+	if nr.HighestPending != nil && *nr.HighestPending >= nr.Committed {
+		return *nr.HighestPending + 1
+	}
+	return nr.Committed
+}
+
+// Gap returns the number of nonces that would be skipped by a transaction with the given nonce.
+// A non-zero gap means that the transaction cannot be executed until transactions with the
+// skipped nonces are executed.
+func (nr *NonceRange) Gap(nonce uint64) uint64 {
+	if next := nr.Next(); nonce > next {
+		return nonce - next
+	}
+	return 0
+}
+
 // BalancesQuery are the arguments for the accounts.Balances query.
 type BalancesQuery struct {
 	Address types.Address `json:"address"`
@@ -47,6 +99,13 @@ type Addresses []types.Address
 // ModuleName is the accounts module name.
 const ModuleName = "accounts"
 
+var (
+	// AddressCommonPool is the address of the common pool account.
+	AddressCommonPool = types.NewAddressForModule(ModuleName, []byte("common-pool"))
+	// AddressFeeAccumulator is the address of the fee accumulator account.
+	AddressFeeAccumulator = types.NewAddressForModule(ModuleName, []byte("fee-accumulator"))
+)
+
 const (
 	// TransferEventCode is the event code for the transfer event.
 	TransferEventCode = 1
@@ -70,6 +129,10 @@ type BurnEvent struct {
 }
 
 // MintEvent is the mint event.
+//
+// Tokens are only minted internally by other runtime modules (e.g. when consensus layer tokens are
+// deposited via consensus_accounts.Deposit). The accounts module does not expose a mint
+// transaction, so test runtimes need to fund accounts via genesis allocations or deposits.
 type MintEvent struct {
 	Owner  types.Address   `json:"owner"`
 	Amount types.BaseUnits `json:"amount"`
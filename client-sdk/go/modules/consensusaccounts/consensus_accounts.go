@@ -135,6 +135,10 @@ func NewV1(rc client.RuntimeClient) V1 {
 	return &v1{rc: rc}
 }
 
+func init() {
+	client.RegisterEventDecoder(ModuleName, &v1{})
+}
+
 // NewDepositTx generates a new consensus.Deposit transaction.
 func NewDepositTx(fee *types.Fee, body *Deposit) *types.Transaction {
 	return types.NewTransaction(fee, methodDeposit, body)
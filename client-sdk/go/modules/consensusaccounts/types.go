@@ -38,6 +38,9 @@ type ConsensusError struct {
 // ModuleName is the consensus accounts module name.
 const ModuleName = "consensus_accounts"
 
+// AddressPendingWithdrawal is the address of the account holding tokens of pending withdrawals.
+var AddressPendingWithdrawal = types.NewAddressForModule(ModuleName, []byte("pending-withdrawal"))
+
 const (
 	// DepositEventCode is the event code for the deposit event.
 	DepositEventCode = 1
@@ -78,3 +81,24 @@ type Event struct {
 	Deposit  *DepositEvent
 	Withdraw *WithdrawEvent
 }
+
+// Result returns the result of the operation the event refers to. In case the operation failed,
+// the module and code of the consensus layer error are returned.
+//
+// An event that does not refer to any operation is reported as failed with an empty module name
+// and a zero code.
+func (e *Event) Result() (bool, string, uint32) {
+	var cerr *ConsensusError
+	switch {
+	case e.Deposit != nil:
+		cerr = e.Deposit.Error
+	case e.Withdraw != nil:
+		cerr = e.Withdraw.Error
+	default:
+		return false, "", 0
+	}
+	if cerr != nil {
+		return false, cerr.Module, cerr.Code
+	}
+	return true, "", 0
+}
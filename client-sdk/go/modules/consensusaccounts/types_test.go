@@ -0,0 +1,29 @@
+package consensusaccounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventResult(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		ev     Event
+		ok     bool
+		module string
+		code   uint32
+	}{
+		{Event{Deposit: &DepositEvent{}}, true, "", 0},
+		{Event{Deposit: &DepositEvent{Error: &ConsensusError{Module: "staking", Code: 5}}}, false, "staking", 5},
+		{Event{Withdraw: &WithdrawEvent{}}, true, "", 0},
+		{Event{Withdraw: &WithdrawEvent{Error: &ConsensusError{Module: "staking", Code: 7}}}, false, "staking", 7},
+		{Event{}, false, "", 0},
+	} {
+		ok, module, code := tc.ev.Result()
+		require.Equal(tc.ok, ok)
+		require.Equal(tc.module, module)
+		require.Equal(tc.code, code)
+	}
+}
@@ -0,0 +1,74 @@
+package contracts
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+)
+
+// codeArtifactVersion is the current version of the code artifact container format.
+const codeArtifactVersion = 1
+
+// codeArtifact is a container for deployable contract code.
+type codeArtifact struct {
+	cbor.Versioned
+
+	// ABI is the ABI that the contract code conforms to.
+	ABI ABI `json:"abi"`
+	// Hash is the hash of the (uncompressed) contract code.
+	Hash hash.Hash `json:"hash"`
+	// Code is the uncompressed contract code.
+	Code []byte `json:"code"`
+}
+
+// ArtifactHash returns the hash of the given uncompressed contract code, as stored in files
+// written by SaveCode.
+//
+// Note that this is not the same as Code.Hash reported by the contracts module, since the module
+// hashes the code after it has been transformed (e.g., instrumented for gas accounting) during
+// upload.
+func ArtifactHash(code []byte) hash.Hash {
+	return hash.NewFromBytes(code)
+}
+
+// SaveCode stores the given (uncompressed) contract code together with its ABI and hash into a
+// file that can later be loaded via LoadCode.
+func SaveCode(path string, code []byte, abi ABI) error {
+	if len(code) == 0 {
+		return fmt.Errorf("missing contract code")
+	}
+
+	artifact := codeArtifact{
+		Versioned: cbor.NewVersioned(codeArtifactVersion),
+		ABI:       abi,
+		Hash:      ArtifactHash(code),
+		Code:      code,
+	}
+	if err := ioutil.WriteFile(path, cbor.Marshal(&artifact), 0o600); err != nil {
+		return fmt.Errorf("failed to write contract code: %w", err)
+	}
+	return nil
+}
+
+// LoadCode loads (uncompressed) contract code and its ABI from a file previously written by
+// SaveCode. An error is returned in case the code does not match the stored hash.
+func LoadCode(path string) ([]byte, ABI, error) {
+	raw, err := ioutil.ReadFile(path) //nolint: gosec
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read contract code: %w", err)
+	}
+
+	var artifact codeArtifact
+	if err = cbor.Unmarshal(raw, &artifact); err != nil {
+		return nil, 0, fmt.Errorf("malformed contract code file: %w", err)
+	}
+	if artifact.V != codeArtifactVersion {
+		return nil, 0, fmt.Errorf("unsupported contract code file version: %d", artifact.V)
+	}
+	if h := ArtifactHash(artifact.Code); !h.Equal(&artifact.Hash) {
+		return nil, 0, fmt.Errorf("contract code hash mismatch (expected: %s got: %s)", artifact.Hash, h)
+	}
+	return artifact.Code, artifact.ABI, nil
+}
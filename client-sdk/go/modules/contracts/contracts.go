@@ -3,14 +3,19 @@ package contracts
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/golang/snappy"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -27,6 +32,10 @@ const (
 	methodInstanceStorage = "contracts.InstanceStorage"
 	methodPublicKey       = "contracts.PublicKey"
 	methodCustom          = "contracts.Custom"
+	methodSimulateCall    = "contracts.SimulateCall"
+
+	// Error codes.
+	errCodeInstanceNotFound = 10
 )
 
 // V1 is the v1 contracts module interface.
@@ -34,7 +43,10 @@ type V1 interface {
 	client.EventDecoder
 
 	// Upload generates a contracts.Upload transaction.
-	Upload(abi ABI, instantiatePolicy Policy, code []byte) *client.TransactionBuilder
+	//
+	// Unless the WithoutCodeValidation option is given, the code is first checked using
+	// ValidateCode so that malformed code is rejected before paying for its upload.
+	Upload(abi ABI, instantiatePolicy Policy, code []byte, opts ...UploadOption) (*client.TransactionBuilder, error)
 
 	// InstantiateRaw generates a contracts.Instantiate transaction.
 	//
@@ -69,15 +81,30 @@ type V1 interface {
 
 	// Upgrade generates a contracts.Upgrade transaction.
 	//
-	// This method will encode the specified data using CBOR as defined by the Oasis ABI.
+	// This method will encode the specified data using CBOR as defined by the Oasis ABI. The data
+	// is passed to the pre-upgrade step of the previous code and to the post-upgrade (migration)
+	// step of the new code. Use CheckUpgradeResult on the submission error to determine whether
+	// the migration succeeded.
+	//
+	// Note that the runtime discards any output of the migration step and a successful upgrade
+	// always has an empty result. Contracts that need to report migration details should emit
+	// events instead.
 	Upgrade(id InstanceID, codeID CodeID, data interface{}, tokens []types.BaseUnits) *client.TransactionBuilder
 
+	// EstimateGas estimates the amount of gas needed to execute the contracts module transaction
+	// generated by the given builder (e.g. by Upload, Instantiate, Call or Upgrade) as if it was
+	// submitted by the given caller. The transaction does not need to be signed.
+	EstimateGas(ctx context.Context, round uint64, caller types.Address, txB *client.TransactionBuilder) (uint64, error)
+
 	// Code queries the given code information.
 	Code(ctx context.Context, round uint64, id CodeID) (*Code, error)
 
 	// Instance queries the given instance information.
 	Instance(ctx context.Context, round uint64, id InstanceID) (*Instance, error)
 
+	// IsInstance checks whether an instance with the given identifier exists.
+	IsInstance(ctx context.Context, round uint64, id InstanceID) (bool, error)
+
 	// InstanceStorage queries the given instance's storage.
 	InstanceStorage(ctx context.Context, round uint64, id InstanceID, key []byte) (*InstanceStorageQueryResult, error)
 
@@ -96,16 +123,62 @@ type V1 interface {
 	// This method will encode the specified data using CBOR as defined by the Oasis ABI.
 	Custom(ctx context.Context, round uint64, id InstanceID, data, rsp interface{}) error
 
+	// SimulateCall simulates calling the given contract instance as the given caller, without
+	// changing any state. The call data is encoded using CBOR as defined by the Oasis ABI.
+	//
+	// The returned result contains the raw data returned by the contract (see
+	// SimulationResult.DecodeData), the gas used and all events that the call would emit (e.g.,
+	// token transfers), with contract events also available in decoded form. Since the events
+	// are collected from the simulated call itself, they are not mixed up with events of other
+	// transactions as they would be when using GetEvents.
+	SimulateCall(ctx context.Context, round uint64, caller types.Address, id InstanceID, data interface{}, tokens []types.BaseUnits) (*SimulationResult, error)
+
+	// InstanceBalances queries the balances of the given instance's account.
+	InstanceBalances(ctx context.Context, round uint64, id InstanceID) (*accounts.AccountBalances, error)
+
 	// GetEvents returns events emitted by the contract at the provided round.
 	GetEvents(ctx context.Context, instanceID InstanceID, round uint64) ([]*Event, error)
+
+	// InstanceUpgrades returns successful upgrades of the given instance performed in blocks
+	// between fromRound and toRound (inclusive), ordered by round. RoundLatest can be used as
+	// toRound.
+	//
+	// The runtime does not record upgrade history, so it is reconstructed by scanning the
+	// transactions of each block. Only upgrades submitted directly as plain contracts.Upgrade
+	// transactions are found, not those using an encrypted call format or issued by contracts.
+	InstanceUpgrades(ctx context.Context, id InstanceID, fromRound, toRound uint64) ([]*InstanceUpgrade, error)
 }
 
 type v1 struct {
 	rc client.RuntimeClient
 }
 
+// UploadOption is an option for Upload.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	skipValidation bool
+}
+
+// WithoutCodeValidation disables checking the code with ValidateCode before upload.
+func WithoutCodeValidation() UploadOption {
+	return func(o *uploadOptions) {
+		o.skipValidation = true
+	}
+}
+
 // Implements V1.
-func (a *v1) Upload(abi ABI, instantiatePolicy Policy, code []byte) *client.TransactionBuilder {
+func (a *v1) Upload(abi ABI, instantiatePolicy Policy, code []byte, opts ...UploadOption) (*client.TransactionBuilder, error) {
+	var o uploadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.skipValidation {
+		if err := ValidateCode(code, abi); err != nil {
+			return nil, err
+		}
+	}
+
 	// Compress code before upload.
 	var compressedCode bytes.Buffer
 	encoder := snappy.NewBufferedWriter(&compressedCode)
@@ -119,7 +192,7 @@ func (a *v1) Upload(abi ABI, instantiatePolicy Policy, code []byte) *client.Tran
 		ABI:               abi,
 		InstantiatePolicy: instantiatePolicy,
 		Code:              compressedCode.Bytes(),
-	})
+	}), nil
 }
 
 // Implements V1.
@@ -166,6 +239,15 @@ func (a *v1) Upgrade(id InstanceID, codeID CodeID, data interface{}, tokens []ty
 	return a.UpgradeRaw(id, codeID, cbor.Marshal(data), tokens)
 }
 
+// Implements V1.
+func (a *v1) EstimateGas(ctx context.Context, round uint64, caller types.Address, txB *client.TransactionBuilder) (uint64, error) {
+	tx := txB.GetTransaction()
+	if tx.Call.Format == types.CallFormatPlain && !strings.HasPrefix(tx.Call.Method, ModuleName+".") {
+		return 0, fmt.Errorf("not a contracts module transaction: %s", tx.Call.Method)
+	}
+	return core.NewV1(a.rc).EstimateGasForCaller(ctx, round, types.CallerAddress{Address: &caller}, tx)
+}
+
 // Implements V1.
 func (a *v1) Code(ctx context.Context, round uint64, id CodeID) (*Code, error) {
 	var code Code
@@ -186,6 +268,18 @@ func (a *v1) Instance(ctx context.Context, round uint64, id InstanceID) (*Instan
 	return &instance, nil
 }
 
+// Implements V1.
+func (a *v1) IsInstance(ctx context.Context, round uint64, id InstanceID) (bool, error) {
+	_, err := a.Instance(ctx, round, id)
+	if module, code := coreErrors.Code(err); module == ModuleName && code == errCodeInstanceNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Implements V1.
 func (a *v1) InstanceStorage(ctx context.Context, round uint64, id InstanceID, key []byte) (*InstanceStorageQueryResult, error) {
 	var rsp InstanceStorageQueryResult
@@ -206,6 +300,11 @@ func (a *v1) PublicKey(ctx context.Context, round uint64, id InstanceID, kind Pu
 	return &pk, nil
 }
 
+// Implements V1.
+func (a *v1) InstanceBalances(ctx context.Context, round uint64, id InstanceID) (*accounts.AccountBalances, error) {
+	return accounts.NewV1(a.rc).Balances(ctx, round, id.Address())
+}
+
 // Implements V1.
 func (a *v1) CustomRaw(ctx context.Context, round uint64, id InstanceID, data []byte) ([]byte, error) {
 	var rsp CustomQueryResult
@@ -228,6 +327,41 @@ func (a *v1) Custom(ctx context.Context, round uint64, id InstanceID, data, rsp
 	return nil
 }
 
+// Implements V1.
+func (a *v1) SimulateCall(ctx context.Context, round uint64, caller types.Address, id InstanceID, data interface{}, tokens []types.BaseUnits) (*SimulationResult, error) {
+	var rsp SimulateCallQueryResult
+	q := &SimulateCallQuery{
+		Caller: caller,
+		ID:     id,
+		Data:   cbor.Marshal(data),
+		Tokens: tokens,
+	}
+	if err := a.rc.Query(ctx, round, methodSimulateCall, q, &rsp); err != nil {
+		return nil, err
+	}
+
+	result := &SimulationResult{
+		Data:    rsp.Data,
+		GasUsed: rsp.GasUsed,
+	}
+	for _, rawEv := range rsp.Events {
+		var ev types.Event
+		if err := ev.UnmarshalRaw(rawEv.Key, rawEv.Value); err != nil {
+			return nil, fmt.Errorf("failed to decode simulated event: %w", err)
+		}
+		result.Events = append(result.Events, &ev)
+
+		decoded, err := a.DecodeEvent(&ev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode simulated event: %w", err)
+		}
+		if cev, ok := decoded.(*Event); ok && cev != nil {
+			result.ContractEvents = append(result.ContractEvents, cev)
+		}
+	}
+	return result, nil
+}
+
 // Implements V1.
 func (a *v1) GetEvents(ctx context.Context, instanceID InstanceID, round uint64) ([]*Event, error) {
 	rawEvs, err := a.rc.GetEventsRaw(ctx, round)
@@ -253,6 +387,52 @@ func (a *v1) GetEvents(ctx context.Context, instanceID InstanceID, round uint64)
 	return evs, nil
 }
 
+// Implements V1.
+func (a *v1) InstanceUpgrades(ctx context.Context, id InstanceID, fromRound, toRound uint64) ([]*InstanceUpgrade, error) {
+	if toRound == client.RoundLatest {
+		blk, err := a.rc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		toRound = blk.Header.Round
+	}
+
+	var upgrades []*InstanceUpgrade
+	for round := fromRound; round <= toRound; round++ {
+		txs, err := a.rc.GetTransactionsWithResults(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transactions for round %d: %w", round, err)
+		}
+
+		for _, txr := range txs {
+			if !txr.Result.IsSuccess() {
+				continue
+			}
+			// Transactions in a block have already been verified.
+			var tx types.Transaction
+			if err = cbor.Unmarshal(txr.Tx.Body, &tx); err != nil {
+				// Not an SDK transaction (e.g. an Ethereum transaction).
+				continue
+			}
+			if tx.Call.Format != types.CallFormatPlain || tx.Call.Method != methodUpgrade {
+				continue
+			}
+			var body Upgrade
+			if err = cbor.Unmarshal(tx.Call.Body, &body); err != nil {
+				continue
+			}
+			if body.ID != id {
+				continue
+			}
+			upgrades = append(upgrades, &InstanceUpgrade{
+				Round:  round,
+				CodeID: body.CodeID,
+			})
+		}
+	}
+	return upgrades, nil
+}
+
 // Implements client.EventDecoder.
 func (a *v1) DecodeEvent(event *types.Event) (client.DecodedEvent, error) {
 	// "contracts" or "contracts.<...>".
@@ -266,7 +446,61 @@ func (a *v1) DecodeEvent(event *types.Event) (client.DecodedEvent, error) {
 	return ev, nil
 }
 
+// DecodeContractError extracts the contract error from an error returned when submitting a
+// contracts module transaction.
+//
+// Returns nil in case the error was not raised by contract code.
+func DecodeContractError(err error) *ContractError {
+	var failed *types.FailedCallResult
+	if !errors.As(err, &failed) {
+		return nil
+	}
+
+	// Contract errors use either "contracts.<code-id>" or "contracts.<code-id>.<module>".
+	if !strings.HasPrefix(failed.Module, ModuleName+".") {
+		return nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(failed.Module, ModuleName+"."), ".", 2)
+	codeID, perr := strconv.ParseUint(parts[0], 10, 64)
+	if perr != nil {
+		return nil
+	}
+	ce := &ContractError{
+		CodeID:  CodeID(codeID),
+		Code:    failed.Code,
+		Message: failed.Message,
+	}
+	if len(parts) > 1 {
+		ce.Module = parts[1]
+	}
+	return ce
+}
+
+// CheckUpgradeResult interprets the error returned when submitting a contracts.Upgrade transaction
+// that upgrades an instance to the given code.
+//
+// In case contract code rejected the upgrade, an *UpgradeError is returned which indicates
+// whether the new code rejected the migration. Other errors are returned unchanged and nil is
+// returned in case the upgrade (including migration) succeeded.
+func CheckUpgradeResult(codeID CodeID, err error) error {
+	if err == nil {
+		return nil
+	}
+	ce := DecodeContractError(err)
+	if ce == nil {
+		return err
+	}
+	return &UpgradeError{
+		ContractError: *ce,
+		Migration:     ce.CodeID == codeID,
+	}
+}
+
 // NewV1 generates a V1 client helper for the contracts module.
 func NewV1(rc client.RuntimeClient) V1 {
 	return &v1{rc: rc}
 }
+
+func init() {
+	client.RegisterEventDecoder(ModuleName, &v1{})
+}
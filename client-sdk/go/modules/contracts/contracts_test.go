@@ -1,10 +1,27 @@
 package contracts
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
 func TestInstanceIDToAddress(t *testing.T) {
@@ -34,3 +51,441 @@ func TestInstanceIDToAddress(t *testing.T) {
 		require.EqualValues(tc.expectedAddress, tc.id.Address().String())
 	}
 }
+
+func TestCheckUpgradeResult(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError(CheckUpgradeResult(2, nil), "successful upgrade")
+
+	other := fmt.Errorf("some error")
+	require.Equal(other, CheckUpgradeResult(2, other), "other errors should pass through")
+
+	moduleErr := &types.FailedCallResult{Module: "contracts", Code: 13}
+	require.Equal(moduleErr, CheckUpgradeResult(2, moduleErr), "module errors should pass through")
+
+	for _, tc := range []struct {
+		failed    types.FailedCallResult
+		codeID    CodeID
+		module    string
+		migration bool
+	}{
+		{types.FailedCallResult{Module: "contracts.2", Code: 1, Message: "bad"}, 2, "", true},
+		{types.FailedCallResult{Module: "contracts.2.test", Code: 1}, 2, "test", true},
+		{types.FailedCallResult{Module: "contracts.1", Code: 5}, 1, "", false},
+	} {
+		err := CheckUpgradeResult(2, fmt.Errorf("wrapped: %w", &tc.failed))
+		require.Error(err)
+
+		var ue *UpgradeError
+		require.ErrorAs(err, &ue)
+		require.EqualValues(tc.codeID, ue.CodeID)
+		require.EqualValues(tc.module, ue.Module)
+		require.EqualValues(tc.failed.Code, ue.Code)
+		require.EqualValues(tc.failed.Message, ue.Message)
+		require.EqualValues(tc.migration, ue.Migration)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	type migration struct {
+		Version uint64 `json:"version"`
+	}
+
+	ctx := context.Background()
+	rc := clienttest.NewMockRuntimeClient()
+	rc.SetSubmitHandler(func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		var decoded types.Transaction
+		if err := cbor.Unmarshal(tx.Body, &decoded); err != nil {
+			return nil, err
+		}
+		var body Upgrade
+		if err := cbor.Unmarshal(decoded.Call.Body, &body); err != nil {
+			return nil, err
+		}
+		var m migration
+		if err := cbor.Unmarshal(body.Data, &m); err != nil {
+			return nil, err
+		}
+		if m.Version < 2 {
+			// The new code rejects the migration.
+			return &types.CallResult{Failed: &types.FailedCallResult{
+				Module:  fmt.Sprintf("contracts.%d", body.CodeID),
+				Code:    1,
+				Message: "unsupported version",
+			}}, nil
+		}
+		return &types.CallResult{Ok: cbor.Marshal(nil)}, nil
+	})
+
+	for _, tc := range []struct {
+		version  uint64
+		rejected bool
+	}{
+		{2, false},
+		{1, true},
+	} {
+		tb := NewV1(rc).Upgrade(1, 2, &migration{Version: tc.version}, nil).
+			SetFeeGas(100_000).
+			AppendAuthSignature(sdkTesting.Alice.SigSpec, 0)
+		err := tb.AppendSign(ctx, sdkTesting.Alice.Signer)
+		require.NoError(err, "AppendSign")
+
+		err = CheckUpgradeResult(2, tb.SubmitTx(ctx, nil))
+		if !tc.rejected {
+			require.NoError(err, "upgrade should succeed")
+			continue
+		}
+		var ue *UpgradeError
+		require.ErrorAs(err, &ue, "upgrade should be rejected")
+		require.True(ue.Migration, "migration should be rejected by the new code")
+		require.Equal("unsupported version", ue.Message)
+	}
+}
+
+func TestUploadValidate(t *testing.T) {
+	require := require.New(t)
+
+	body := Upload{ABI: ABIOasisV1}
+	require.Error(body.Validate(), "upload without code should be rejected")
+
+	body.Code = []byte{0x00, 0x61, 0x73, 0x6d}
+	require.NoError(body.Validate())
+}
+
+func TestParsePolicy(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		policy string
+		valid  bool
+	}{
+		{"nobody", true},
+		{"everyone", true},
+		{"address:oasis1qpg6jv8mxwlv4z578xyjxl7d793jamltdg9czzkx", true},
+		{"address:oasis1invalid", false},
+		{"address:", false},
+		{"owner", false},
+		{"", false},
+	} {
+		p, err := ParsePolicy(tc.policy)
+		if !tc.valid {
+			require.Error(err, "ParsePolicy should fail for '%s'", tc.policy)
+			continue
+		}
+		require.NoError(err, "ParsePolicy")
+		require.Equal(tc.policy, p.String(), "policy should round-trip")
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	require := require.New(t)
+
+	type sayHello struct {
+		Who string `json:"who"`
+	}
+
+	raw := EncodeRequest("say_hello", &sayHello{Who: "world"})
+	var decoded sayHello
+	err := DecodeResponse(raw, "say_hello", &decoded)
+	require.NoError(err, "DecodeResponse")
+	require.Equal("world", decoded.Who)
+
+	err = DecodeResponse(raw, "hello", &decoded)
+	require.Error(err, "DecodeResponse should fail for a different variant")
+
+	err = DecodeResponse(EncodeRequest("empty", nil), "empty", nil)
+	require.NoError(err, "DecodeResponse of a variant without fields")
+
+	err = DecodeResponse([]byte{0xa0}, "empty", nil)
+	require.Error(err, "DecodeResponse should fail for an empty envelope")
+}
+
+func TestSaveLoadCode(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-sdk-contracts-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "code.bin")
+	code := []byte("\x00asm test contract code")
+	err = SaveCode(path, code, ABIOasisV1)
+	require.NoError(err, "SaveCode")
+
+	loaded, abi, err := LoadCode(path)
+	require.NoError(err, "LoadCode")
+	require.Equal(code, loaded, "loaded code should match")
+	require.Equal(ABIOasisV1, abi, "loaded ABI should match")
+
+	err = SaveCode(path, nil, ABIOasisV1)
+	require.Error(err, "SaveCode should fail without code")
+
+	// Tamper with the stored code.
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(err, "ReadFile")
+	raw[len(raw)-1] ^= 0xff
+	err = ioutil.WriteFile(path, raw, 0o600)
+	require.NoError(err, "WriteFile")
+	_, _, err = LoadCode(path)
+	require.Error(err, "LoadCode should fail on hash mismatch")
+
+	_, _, err = LoadCode(filepath.Join(dir, "missing.bin"))
+	require.Error(err, "LoadCode should fail on missing file")
+}
+
+func TestEstimateGas(t *testing.T) {
+	require := require.New(t)
+
+	var args core.EstimateGasQuery
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryHandler("core.EstimateGas", func(round uint64, rawArgs cbor.RawMessage) (interface{}, error) {
+		if err := cbor.Unmarshal(rawArgs, &args); err != nil {
+			return nil, err
+		}
+		return uint64(42), nil
+	})
+	ct := NewV1(mc)
+	caller := sdkTesting.Alice.Address
+
+	gas, err := ct.EstimateGas(context.Background(), client.RoundLatest, caller, ct.Call(1, nil, nil))
+	require.NoError(err, "EstimateGas")
+	require.EqualValues(42, gas)
+	require.Equal(&caller, args.Caller.Address, "estimation should be performed for the caller")
+	require.Equal(methodCall, args.Tx.Call.Method)
+
+	_, err = ct.EstimateGas(context.Background(), client.RoundLatest, caller, client.NewTransactionBuilder(mc, "accounts.Transfer", nil))
+	require.Error(err, "EstimateGas should fail for other modules' transactions")
+}
+
+func TestInstanceBalances(t *testing.T) {
+	require := require.New(t)
+
+	id := InstanceID(7)
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryHandler("accounts.Balances", func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		var q accounts.BalancesQuery
+		if err := cbor.Unmarshal(args, &q); err != nil {
+			return nil, err
+		}
+		require.Equal(id.Address(), q.Address, "balances of the instance address should be queried")
+		return &accounts.AccountBalances{
+			Balances: map[types.Denomination]types.Quantity{
+				types.NativeDenomination: *quantity.NewFromUint64(42),
+			},
+		}, nil
+	})
+
+	balances, err := NewV1(mc).InstanceBalances(context.Background(), client.RoundLatest, id)
+	require.NoError(err, "InstanceBalances")
+	native := balances.Balances[types.NativeDenomination]
+	require.EqualValues(42, native.ToBigInt().Int64())
+}
+
+func TestInstanceUpgrades(t *testing.T) {
+	require := require.New(t)
+
+	newTx := func(method string, body interface{}, format types.CallFormat, result types.CallResult) *client.TransactionWithResults {
+		tx := types.NewTransaction(nil, method, body)
+		tx.Call.Format = format
+		return &client.TransactionWithResults{
+			Tx:     types.UnverifiedTransaction{Body: cbor.Marshal(tx), AuthProofs: []types.AuthProof{}},
+			Result: result,
+		}
+	}
+	ok := types.CallResult{Ok: cbor.Marshal(nil)}
+	failed := types.CallResult{Failed: &types.FailedCallResult{Module: ModuleName, Code: 1}}
+	upgrade := func(id InstanceID, codeID CodeID, format types.CallFormat, result types.CallResult) *client.TransactionWithResults {
+		return newTx(methodUpgrade, &Upgrade{ID: id, CodeID: codeID}, format, result)
+	}
+
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetTransactions(1, []*client.TransactionWithResults{
+		upgrade(1, 2, types.CallFormatPlain, ok),
+		// Upgrades of other instances are ignored.
+		upgrade(2, 5, types.CallFormatPlain, ok),
+	})
+	mc.SetTransactions(2, []*client.TransactionWithResults{
+		// Failed upgrades are ignored.
+		upgrade(1, 3, types.CallFormatPlain, failed),
+		// Other methods are ignored.
+		newTx(methodCall, &Call{ID: 1}, types.CallFormatPlain, ok),
+		// Non-SDK transactions are ignored.
+		{Tx: types.UnverifiedTransaction{Body: []byte("not an sdk transaction")}, Result: ok},
+	})
+	mc.SetTransactions(3, []*client.TransactionWithResults{
+		// Encrypted calls cannot be inspected.
+		upgrade(1, 4, types.CallFormatEncryptedX25519DeoxysII, ok),
+		upgrade(1, 6, types.CallFormatPlain, ok),
+	})
+	for round := uint64(1); round <= 3; round++ {
+		var blk block.Block
+		blk.Header.Round = round
+		mc.SetBlock(&blk)
+	}
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		fromRound uint64
+		toRound   uint64
+		expected  []*InstanceUpgrade
+	}{
+		{1, client.RoundLatest, []*InstanceUpgrade{{Round: 1, CodeID: 2}, {Round: 3, CodeID: 6}}},
+		{1, 3, []*InstanceUpgrade{{Round: 1, CodeID: 2}, {Round: 3, CodeID: 6}}},
+		{2, client.RoundLatest, []*InstanceUpgrade{{Round: 3, CodeID: 6}}},
+		{2, 2, nil},
+	} {
+		upgrades, err := NewV1(mc).InstanceUpgrades(ctx, 1, tc.fromRound, tc.toRound)
+		require.NoError(err, "InstanceUpgrades")
+		require.Equal(tc.expected, upgrades, "InstanceUpgrades(%d, %d)", tc.fromRound, tc.toRound)
+	}
+}
+
+func TestSimulateCall(t *testing.T) {
+	require := require.New(t)
+
+	var args SimulateCallQuery
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryHandler(methodSimulateCall, func(round uint64, rawArgs cbor.RawMessage) (interface{}, error) {
+		if err := cbor.Unmarshal(rawArgs, &args); err != nil {
+			return nil, err
+		}
+		return &SimulateCallQueryResult{
+			Data:    cbor.Marshal("hello"),
+			GasUsed: 42,
+			Events: []SimulatedEvent{
+				{Key: types.NewEventKey(ModuleName, 1), Value: cbor.Marshal(nil)},
+				{Key: types.NewEventKey(ModuleName+".7", 1), Value: cbor.Marshal(&Event{ID: 7, Data: cbor.Marshal("greeted")})},
+				{Key: types.NewEventKey("accounts", 1), Value: cbor.Marshal(nil)},
+			},
+		}, nil
+	})
+	caller := sdkTesting.Alice.Address
+	result, err := NewV1(mc).SimulateCall(context.Background(), client.RoundLatest, caller, 7, "say_hello", nil)
+	require.NoError(err, "SimulateCall")
+	require.Equal(caller, args.Caller)
+	require.EqualValues(7, args.ID)
+	require.Equal(cbor.Marshal("say_hello"), args.Data, "call data should be CBOR-encoded")
+
+	var data string
+	require.NoError(result.DecodeData(&data), "result data should be returned")
+	require.Equal("hello", data)
+	require.Error(result.DecodeData(new(uint64)), "DecodeData should fail for a mismatched type")
+	require.EqualValues(42, result.GasUsed)
+	require.Len(result.Events, 3, "all emitted events should be returned")
+	require.Equal(ModuleName, result.Events[0].Module)
+	require.EqualValues(1, result.Events[0].Code)
+	require.Len(result.ContractEvents, 1, "contract events should be decoded")
+	require.EqualValues(7, result.ContractEvents[0].ID)
+	require.Equal(cbor.Marshal("greeted"), result.ContractEvents[0].Data)
+}
+
+func TestIsInstance(t *testing.T) {
+	require := require.New(t)
+
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryHandler(methodInstance, func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		var q InstanceQuery
+		if err := cbor.Unmarshal(args, &q); err != nil {
+			return nil, err
+		}
+		switch q.ID {
+		case 7:
+			return &Instance{ID: 7, CodeID: 1}, nil
+		case 8:
+			return nil, coreErrors.FromCode(ModuleName, errCodeInstanceNotFound, "instance not found")
+		default:
+			return nil, coreErrors.FromCode(ModuleName, 1, "invalid argument")
+		}
+	})
+	ct := NewV1(mc)
+
+	for _, tc := range []struct {
+		id      InstanceID
+		exists  bool
+		noError bool
+		msg     string
+	}{
+		{7, true, true, "existing instance"},
+		{8, false, true, "missing instance"},
+		{9, false, false, "other errors should be propagated"},
+	} {
+		exists, err := ct.IsInstance(context.Background(), client.RoundLatest, tc.id)
+		if !tc.noError {
+			require.Error(err, tc.msg)
+			continue
+		}
+		require.NoError(err, tc.msg)
+		require.Equal(tc.exists, exists, tc.msg)
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	require := require.New(t)
+
+	vec := func(items ...[]byte) []byte {
+		out := []byte{byte(len(items))}
+		for _, item := range items {
+			out = append(out, item...)
+		}
+		return out
+	}
+	name := func(s string) []byte {
+		return append([]byte{byte(len(s))}, s...)
+	}
+	section := func(id byte, content []byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	export := func(s string) []byte {
+		return append(name(s), 0x00, 0x00)
+	}
+	module := func(sections ...[]byte) []byte {
+		code := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+		for _, s := range sections {
+			code = append(code, s...)
+		}
+		return code
+	}
+	exports := func(names ...string) []byte {
+		var items [][]byte
+		for _, n := range names {
+			items = append(items, export(n))
+		}
+		return section(wasmSectionExport, vec(items...))
+	}
+	required := []string{"allocate", "deallocate", "instantiate", "call"}
+	memory := section(wasmSectionMemory, vec([]byte{0x00, 0x01}))
+	importedMemory := section(wasmSectionImport, vec(append(append(name("env"), name("memory")...), wasmImportKindMemory, 0x01, 0x01, 0x02)))
+	importedFunc := section(wasmSectionImport, vec(append(append(name("env"), name("print")...), wasmImportKindFunction, 0x00)))
+
+	valid := module(importedFunc, memory, exports(append(required, "query")...))
+	require.NoError(ValidateCode(valid, ABIOasisV1), "valid code")
+	require.NoError(ValidateCode(module(importedMemory, exports(required...)), ABIOasisV1), "valid code with imported memory")
+
+	for _, tc := range []struct {
+		code []byte
+		abi  ABI
+		msg  string
+	}{
+		{valid, ABI(42), "unsupported ABI"},
+		{[]byte("not wasm"), ABIOasisV1, "not a Wasm module"},
+		{valid[:len(valid)-3], ABIOasisV1, "truncated code"},
+		{module(memory, exports("allocate", "deallocate", "call")), ABIOasisV1, "missing required export"},
+		{module(memory, exports(append(required, "gas_limit")...)), ABIOasisV1, "reserved export"},
+		{module(memory, exports(required...), section(wasmSectionStart, []byte{0x00})), ABIOasisV1, "start function"},
+		{module(importedMemory, memory, exports(required...)), ABIOasisV1, "too many memories"},
+	} {
+		require.Error(ValidateCode(tc.code, tc.abi), tc.msg)
+	}
+
+	ct := NewV1(nil)
+	_, err := ct.Upload(ABIOasisV1, Policy{Everyone: &struct{}{}}, []byte("not wasm"))
+	require.Error(err, "Upload should validate code")
+	tb, err := ct.Upload(ABIOasisV1, Policy{Everyone: &struct{}{}}, []byte("not wasm"), WithoutCodeValidation())
+	require.NoError(err, "Upload should skip validation when requested")
+	require.Equal(methodUpload, tb.GetTransaction().Call.Method)
+	tb, err = ct.Upload(ABIOasisV1, Policy{Everyone: &struct{}{}}, valid)
+	require.NoError(err, "Upload")
+	require.Equal(methodUpload, tb.GetTransaction().Call.Method)
+}
@@ -0,0 +1,53 @@
+package contracts
+
+import (
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+)
+
+// Contracts conforming to the Oasis ABI (ABIOasisV1) define their requests and responses as Rust
+// enums which are CBOR-encoded as a map with a single entry. The entry key is the (renamed)
+// variant name and the entry value is the variant body, for example:
+//
+//   {"say_hello": {"who": "world"}}
+//
+// The encoded request envelope can be passed to InstantiateRaw, CallRaw, UpgradeRaw or CustomRaw
+// and the response envelope is returned as the call (or custom query) result. Any execution
+// metadata (emitted messages and events) is handled by the runtime and is not part of the result.
+
+// EncodeRequest encodes a contract request envelope for the given variant and body.
+//
+// A nil body is encoded as an empty map, as used by variants without fields.
+func EncodeRequest(variant string, body interface{}) []byte {
+	if body == nil {
+		body = struct{}{}
+	}
+	return cbor.Marshal(map[string]interface{}{variant: body})
+}
+
+// DecodeResponse decodes a contract response envelope and unmarshals the body of the given
+// variant into out (if not nil). An error is returned in case the response is of a different
+// variant.
+func DecodeResponse(raw []byte, variant string, out interface{}) error {
+	var envelope map[string]cbor.RawMessage
+	if err := cbor.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("malformed contract response: %w", err)
+	}
+	if len(envelope) != 1 {
+		return fmt.Errorf("malformed contract response: expected a single variant, got %d", len(envelope))
+	}
+	body, ok := envelope[variant]
+	if !ok {
+		for v := range envelope {
+			return fmt.Errorf("unexpected contract response variant '%s' (expected '%s')", v, variant)
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := cbor.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("malformed contract response body: %w", err)
+	}
+	return nil
+}
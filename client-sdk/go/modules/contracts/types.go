@@ -2,7 +2,10 @@ package contracts
 
 import (
 	"encoding/binary"
+	"fmt"
+	"strings"
 
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
@@ -28,6 +31,45 @@ type Policy struct {
 	Everyone *struct{}      `json:"everyone,omitempty"`
 }
 
+const (
+	policyNobody        = "nobody"
+	policyEveryone      = "everyone"
+	policyAddressPrefix = "address:"
+)
+
+// String returns a string representation of the policy, as accepted by ParsePolicy.
+func (p Policy) String() string {
+	switch {
+	case p.Nobody != nil:
+		return policyNobody
+	case p.Address != nil:
+		return policyAddressPrefix + p.Address.String()
+	case p.Everyone != nil:
+		return policyEveryone
+	default:
+		return "[invalid policy]"
+	}
+}
+
+// ParsePolicy parses a policy from its string representation which is one of "nobody",
+// "everyone" or "address:<bech32 address>".
+func ParsePolicy(s string) (Policy, error) {
+	switch {
+	case s == policyNobody:
+		return Policy{Nobody: &struct{}{}}, nil
+	case s == policyEveryone:
+		return Policy{Everyone: &struct{}{}}, nil
+	case strings.HasPrefix(s, policyAddressPrefix):
+		var addr types.Address
+		if err := addr.UnmarshalText([]byte(strings.TrimPrefix(s, policyAddressPrefix))); err != nil {
+			return Policy{}, fmt.Errorf("malformed policy address: %w", err)
+		}
+		return Policy{Address: &addr}, nil
+	default:
+		return Policy{}, fmt.Errorf("unknown policy '%s' (expected nobody, everyone or address:<addr>)", s)
+	}
+}
+
 // ABI is the ABI that the given contract should conform to.
 type ABI uint8
 
@@ -62,6 +104,14 @@ type Instance struct {
 	UpgradesPolicy Policy `json:"upgrades_policy"`
 }
 
+// InstanceUpgrade is a past upgrade of an instance.
+type InstanceUpgrade struct {
+	// Round is the round in which the upgrade was performed.
+	Round uint64
+	// CodeID is the identifier of code used by the instance after the upgrade.
+	CodeID CodeID
+}
+
 // Upload is the body of the contracts.Upload call.
 type Upload struct {
 	// ABI.
@@ -72,6 +122,14 @@ type Upload struct {
 	Code []byte `json:"code"`
 }
 
+// Validate implements client.BodyValidator.
+func (u *Upload) Validate() error {
+	if len(u.Code) == 0 {
+		return fmt.Errorf("missing contract code")
+	}
+	return nil
+}
+
 // UploadResult is the result of the contracts.Upload call.
 type UploadResult struct {
 	// ID is the assigned code identifier.
@@ -115,7 +173,8 @@ type Upgrade struct {
 	ID InstanceID `json:"id"`
 	// CodeID is the identifier of updated code to be used by the instance.
 	CodeID CodeID `json:"code_id"`
-	// Data are the arguments to contract's instantiation function.
+	// Data are the arguments to the pre-upgrade function of the previous code and the
+	// post-upgrade (migration) function of the new code.
 	Data []byte `json:"data"`
 	// Tokens that should be sent to the contract as part of the upgrade call.
 	Tokens []types.BaseUnits `json:"tokens"`
@@ -184,9 +243,102 @@ type CustomQuery struct {
 // CustomQueryResult is the result of the contracts.Custom query.
 type CustomQueryResult []byte
 
+// SimulateCallQuery is the body of the contracts.SimulateCall query.
+type SimulateCallQuery struct {
+	// Caller is the address of the caller.
+	Caller types.Address `json:"caller"`
+	// ID is the instance identifier.
+	ID InstanceID `json:"id"`
+	// Data are the arguments to contract's call function.
+	Data []byte `json:"data"`
+	// Tokens that should be sent to the contract as part of the call.
+	Tokens []types.BaseUnits `json:"tokens,omitempty"`
+	// GasLimit is the gas limit for the simulated call. If zero, the node-configured limit for
+	// custom queries is used.
+	GasLimit uint64 `json:"gas_limit,omitempty"`
+}
+
+// SimulatedEvent is a raw event emitted during a simulated call.
+type SimulatedEvent struct {
+	// Key is the raw event key.
+	Key []byte `json:"key"`
+	// Value is the raw event value.
+	Value []byte `json:"value"`
+}
+
+// SimulateCallQueryResult is the result of the contracts.SimulateCall query.
+type SimulateCallQueryResult struct {
+	// Data is the data returned by the contract call.
+	Data []byte `json:"data"`
+	// GasUsed is the amount of gas used by the call.
+	GasUsed uint64 `json:"gas_used"`
+	// Events are the events that would be emitted by the call.
+	Events []SimulatedEvent `json:"events"`
+}
+
+// SimulationResult is the outcome of a simulated contract call.
+type SimulationResult struct {
+	// Data is the data returned by the contract call.
+	Data []byte
+	// GasUsed is the amount of gas used by the call.
+	GasUsed uint64
+	// Events are the events that would be emitted by the call.
+	Events []*types.Event
+	// ContractEvents are the events that would be emitted by contract code during the call,
+	// decoded from Events.
+	ContractEvents []*Event
+}
+
+// DecodeData decodes the data returned by the simulated contract call into rsp using CBOR.
+func (sr *SimulationResult) DecodeData(rsp interface{}) error {
+	if err := cbor.Unmarshal(sr.Data, rsp); err != nil {
+		return fmt.Errorf("failed to decode simulation result data: %w", err)
+	}
+	return nil
+}
+
 // ModuleName is the contracts module name.
 const ModuleName = "contracts"
 
+// ContractError is an error raised by contract code during execution.
+type ContractError struct {
+	// CodeID is the identifier of the code that raised the error.
+	CodeID CodeID
+	// Module is the contract-defined module name (may be empty).
+	Module string
+	// Code is the contract-defined error code.
+	Code uint32
+	// Message is the error message.
+	Message string
+}
+
+// Error is a trivial implementation of error.
+func (ce *ContractError) Error() string {
+	return fmt.Sprintf("contract error: code_id: %d module: %s code: %d message: %s", ce.CodeID, ce.Module, ce.Code, ce.Message)
+}
+
+// UpgradeError is the error returned when contract code rejects an upgrade.
+type UpgradeError struct {
+	ContractError
+
+	// Migration is true in case the new code rejected the migration in its post-upgrade step and
+	// false in case the previous code refused the upgrade in its pre-upgrade step.
+	Migration bool
+}
+
+// Error is a trivial implementation of error.
+func (ue *UpgradeError) Error() string {
+	if ue.Migration {
+		return fmt.Sprintf("upgrade migration rejected by new code: %s", ue.ContractError.Error())
+	}
+	return fmt.Sprintf("upgrade rejected by previous code: %s", ue.ContractError.Error())
+}
+
+// Unwrap returns the underlying contract error.
+func (ue *UpgradeError) Unwrap() error {
+	return &ue.ContractError
+}
+
 // Event is an event emitted by a contract.
 type Event struct {
 	// ID is the instance identifier.
@@ -0,0 +1,243 @@
+package contracts
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Wasm binary format constants.
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+const (
+	wasmSectionImport = 2
+	wasmSectionMemory = 5
+	wasmSectionExport = 7
+	wasmSectionStart  = 8
+
+	wasmImportKindFunction = 0
+	wasmImportKindTable    = 1
+	wasmImportKindMemory   = 2
+	wasmImportKindGlobal   = 3
+)
+
+// abiRequirements are the requirements an ABI imposes on contract code.
+type abiRequirements struct {
+	// requiredExports are the names that the code must export.
+	requiredExports []string
+	// reservedExports are the names that the code must not export.
+	reservedExports []string
+}
+
+var abiRequirementsByABI = map[ABI]*abiRequirements{
+	ABIOasisV1: {
+		requiredExports: []string{"allocate", "deallocate", "instantiate", "call"},
+		reservedExports: []string{"gas_limit", "gas_limit_exhausted"},
+	},
+}
+
+// ValidateCode checks that the given (uncompressed) code is a well-formed Wasm module conforming
+// to the given ABI, the same way the contracts module does on upload. This makes it possible to
+// reject malformed code before paying for its upload.
+func ValidateCode(code []byte, abi ABI) error {
+	reqs, ok := abiRequirementsByABI[abi]
+	if !ok {
+		return fmt.Errorf("contracts: unsupported ABI %d", abi)
+	}
+
+	if !bytes.HasPrefix(code, wasmMagic) {
+		return fmt.Errorf("contracts: code is not a Wasm module")
+	}
+
+	exports := make(map[string]bool)
+	var memories uint64
+	r := wasmReader{data: code[len(wasmMagic):]}
+	for !r.done() {
+		id, err := r.byte()
+		if err != nil {
+			return fmt.Errorf("contracts: malformed code: %w", err)
+		}
+		section, err := r.bytes()
+		if err != nil {
+			return fmt.Errorf("contracts: malformed code section %d: %w", id, err)
+		}
+
+		sr := wasmReader{data: section}
+		switch id {
+		case wasmSectionImport:
+			var n uint64
+			if n, err = sr.importedMemories(); err != nil {
+				return fmt.Errorf("contracts: malformed import section: %w", err)
+			}
+			memories += n
+		case wasmSectionMemory:
+			var n uint64
+			if n, err = sr.u32(); err != nil {
+				return fmt.Errorf("contracts: malformed memory section: %w", err)
+			}
+			memories += n
+		case wasmSectionExport:
+			if err = sr.exports(exports); err != nil {
+				return fmt.Errorf("contracts: malformed export section: %w", err)
+			}
+		case wasmSectionStart:
+			return fmt.Errorf("contracts: code declares start function")
+		}
+	}
+
+	for _, name := range reqs.requiredExports {
+		if !exports[name] {
+			return fmt.Errorf("contracts: code is missing required ABI export: %s", name)
+		}
+	}
+	for _, name := range reqs.reservedExports {
+		if exports[name] {
+			return fmt.Errorf("contracts: code declares reserved ABI export: %s", name)
+		}
+	}
+	if memories > 1 {
+		return fmt.Errorf("contracts: code declares too many memories")
+	}
+	return nil
+}
+
+// wasmReader is a reader for the Wasm binary format.
+type wasmReader struct {
+	data []byte
+}
+
+func (r *wasmReader) done() bool {
+	return len(r.data) == 0
+}
+
+func (r *wasmReader) byte() (byte, error) {
+	if len(r.data) == 0 {
+		return 0, fmt.Errorf("unexpected end of data")
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b, nil
+}
+
+// u32 reads an unsigned LEB128-encoded 32-bit integer.
+func (r *wasmReader) u32() (uint64, error) {
+	var result uint64
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if result > 0xffffffff {
+				return 0, fmt.Errorf("integer too large")
+			}
+			return result, nil
+		}
+	}
+	return 0, fmt.Errorf("integer too large")
+}
+
+// bytes reads a length-prefixed byte vector.
+func (r *wasmReader) bytes() ([]byte, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.data)) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// skip skips the given number of bytes.
+func (r *wasmReader) skip(n int) error {
+	if n > len(r.data) {
+		return fmt.Errorf("unexpected end of data")
+	}
+	r.data = r.data[n:]
+	return nil
+}
+
+// limits skips resizable limits.
+func (r *wasmReader) limits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err = r.u32(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		if _, err = r.u32(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importedMemories reads the contents of an import section and returns the number of imported
+// memories.
+func (r *wasmReader) importedMemories() (uint64, error) {
+	count, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	var memories uint64
+	for i := uint64(0); i < count; i++ {
+		// Module and field names.
+		if _, err = r.bytes(); err != nil {
+			return 0, err
+		}
+		if _, err = r.bytes(); err != nil {
+			return 0, err
+		}
+		kind, kerr := r.byte()
+		if kerr != nil {
+			return 0, kerr
+		}
+		switch kind {
+		case wasmImportKindFunction:
+			_, err = r.u32()
+		case wasmImportKindTable:
+			if err = r.skip(1); err == nil {
+				err = r.limits()
+			}
+		case wasmImportKindMemory:
+			memories++
+			err = r.limits()
+		case wasmImportKindGlobal:
+			err = r.skip(2)
+		default:
+			err = fmt.Errorf("unknown import kind %d", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return memories, nil
+}
+
+// exports reads the contents of an export section and records the names of all exports.
+func (r *wasmReader) exports(names map[string]bool) error {
+	count, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		name, nerr := r.bytes()
+		if nerr != nil {
+			return nerr
+		}
+		// Export kind and index.
+		if err = r.skip(1); err != nil {
+			return err
+		}
+		if _, err = r.u32(); err != nil {
+			return err
+		}
+		names[string(name)] = true
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
@@ -9,13 +10,22 @@ import (
 
 const (
 	// Queries.
-	methodEstimateGas = "core.EstimateGas"
-	methodMinGasPrice = "core.MinGasPrice"
+	methodEstimateGas      = "core.EstimateGas"
+	methodMinGasPrice      = "core.MinGasPrice"
+	methodGasCosts         = "core.GasCosts"
+	methodParameters       = "core.Parameters"
+	methodSupportedMethods = "core.SupportedMethods"
 )
 
 // V1 is the v1 core module interface.
 type V1 interface {
-	// EstimateGas performs gas estimation for executing the given transaction.
+	// WithDefaultCaller returns a new V1 instance that performs gas estimation via EstimateGas as
+	// if the given caller had executed the transaction, instead of using the authentication
+	// information from the passed transaction.
+	WithDefaultCaller(caller types.CallerAddress) V1
+
+	// EstimateGas performs gas estimation for executing the given transaction. In case a default
+	// caller has been configured via WithDefaultCaller, estimation is performed for that caller.
 	EstimateGas(ctx context.Context, round uint64, tx *types.Transaction) (uint64, error)
 
 	// EstimateGasForCaller performs gas estimation for executing the given transaction as if the
@@ -24,16 +34,45 @@ type V1 interface {
 
 	// MinGasPrice returns the minimum gas price.
 	MinGasPrice(ctx context.Context) (map[types.Denomination]types.Quantity, error)
+
+	// MinGasPriceForDenomination returns the minimum gas price for paying fees in the given
+	// denomination. An error is returned in case the runtime does not accept fees in the given
+	// denomination.
+	MinGasPriceForDenomination(ctx context.Context, denomination types.Denomination) (*types.Quantity, error)
+
+	// GasCosts returns the gas costs charged by the core module.
+	GasCosts(ctx context.Context, round uint64) (*GasCosts, error)
+
+	// Parameters queries the core module parameters.
+	//
+	// The returned parameters can be used to check transactions before submission via
+	// Parameters.CheckTransaction.
+	Parameters(ctx context.Context, round uint64) (*Parameters, error)
+
+	// SupportedMethods returns the callable methods and queries supported by the runtime. It can
+	// be used to detect whether a runtime supports a given module (e.g., contracts) before
+	// issuing any calls.
+	SupportedMethods(ctx context.Context, round uint64) (*MethodList, error)
 }
 
 type v1 struct {
-	rc client.RuntimeClient
+	rc     client.RuntimeClient
+	caller *types.CallerAddress
+}
+
+// Implements V1.
+func (a *v1) WithDefaultCaller(caller types.CallerAddress) V1 {
+	return &v1{rc: a.rc, caller: &caller}
 }
 
 // Implements V1.
 func (a *v1) EstimateGas(ctx context.Context, round uint64, tx *types.Transaction) (uint64, error) {
 	var gas uint64
-	err := a.rc.Query(ctx, round, methodEstimateGas, EstimateGasQuery{Tx: tx}, &gas)
+	args := EstimateGasQuery{
+		Caller: a.caller,
+		Tx:     tx,
+	}
+	err := a.rc.Query(ctx, round, methodEstimateGas, args, &gas)
 	if err != nil {
 		return 0, err
 	}
@@ -64,6 +103,49 @@ func (a *v1) MinGasPrice(ctx context.Context) (map[types.Denomination]types.Quan
 	return mgp, nil
 }
 
+// Implements V1.
+func (a *v1) MinGasPriceForDenomination(ctx context.Context, denomination types.Denomination) (*types.Quantity, error) {
+	mgp, err := a.MinGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	price, ok := mgp[denomination]
+	if !ok {
+		return nil, fmt.Errorf("fees cannot be paid in denomination '%s'", denomination)
+	}
+	return &price, nil
+}
+
+// Implements V1.
+func (a *v1) GasCosts(ctx context.Context, round uint64) (*GasCosts, error) {
+	var gc GasCosts
+	err := a.rc.Query(ctx, round, methodGasCosts, nil, &gc)
+	if err != nil {
+		return nil, err
+	}
+	return &gc, nil
+}
+
+// Implements V1.
+func (a *v1) Parameters(ctx context.Context, round uint64) (*Parameters, error) {
+	var params Parameters
+	err := a.rc.Query(ctx, round, methodParameters, nil, &params)
+	if err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// Implements V1.
+func (a *v1) SupportedMethods(ctx context.Context, round uint64) (*MethodList, error) {
+	var methods MethodList
+	err := a.rc.Query(ctx, round, methodSupportedMethods, nil, &methods)
+	if err != nil {
+		return nil, err
+	}
+	return &methods, nil
+}
+
 // NewV1 generates a V1 client helper for the core module.
 func NewV1(rc client.RuntimeClient) V1 {
 	return &v1{rc: rc}
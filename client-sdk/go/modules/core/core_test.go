@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	sdkTesting "github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestCheckTransaction(t *testing.T) {
+	require := require.New(t)
+
+	params := Parameters{
+		MaxBatchGas:        10_000,
+		MaxTxSigners:       1,
+		MaxMultisigSigners: 2,
+		GasCosts:           GasCosts{TxByte: 1},
+		MinGasPrice: map[types.Denomination]types.Quantity{
+			types.NativeDenomination: {},
+		},
+	}
+	newTx := func(body []byte, gas uint64) *types.Transaction {
+		tx := types.NewTransaction(&types.Fee{Gas: gas}, "test.Method", body)
+		tx.AppendAuthSignature(types.SignatureAddressSpec{}, 0)
+		return tx
+	}
+
+	require.NoError(params.CheckTransaction(newTx(nil, 1_000)), "valid transaction")
+	require.NoError(params.CheckTransaction(newTx(nil, 0)), "transaction without gas limit")
+
+	require.Error(params.CheckTransaction(newTx(nil, 10_001)), "gas limit exceeding batch gas")
+	require.Error(params.CheckTransaction(newTx(make([]byte, 2_000), 1_000)), "gas limit too low for size")
+	require.Error(params.CheckTransaction(newTx(make([]byte, 20_000), 10_000)), "transaction too large")
+
+	tx := newTx(nil, 1_000)
+	tx.AppendAuthSignature(types.SignatureAddressSpec{}, 0)
+	require.Error(params.CheckTransaction(tx), "too many signers")
+
+	tx = newTx(nil, 1_000)
+	tx.AuthInfo.Fee.Amount.Denomination = "FOO"
+	require.Error(params.CheckTransaction(tx), "unsupported fee denomination")
+
+	tx = types.NewTransaction(&types.Fee{Gas: 1_000}, "test.Method", nil)
+	tx.AppendAuthMultisig(&types.MultisigConfig{Signers: make([]types.MultisigSigner, 3)}, 0)
+	require.Error(params.CheckTransaction(tx), "too many multisig signers")
+}
+
+func TestMethodList(t *testing.T) {
+	require := require.New(t)
+
+	var methods MethodList
+	raw := cbor.Marshal([]map[string]interface{}{
+		{"kind": 1, "name": "accounts.Transfer"},
+		{"kind": 2, "name": "accounts.Balances"},
+		{"kind": 2, "name": "core.EstimateGas"},
+		{"kind": 1, "name": "contracts.Call"},
+	})
+	require.NoError(cbor.Unmarshal(raw, &methods), "method list should decode")
+
+	require.Equal([]string{"accounts.Transfer", "contracts.Call"}, methods.Calls())
+	require.Equal([]string{"accounts.Balances", "core.EstimateGas"}, methods.Queries())
+	require.Equal([]string{"accounts", "contracts", "core"}, methods.Modules())
+	require.True(methods.HasMethod(MethodHandlerKindCall, "contracts.Call"))
+	require.False(methods.HasMethod(MethodHandlerKindQuery, "contracts.Call"), "method kind should match")
+	require.False(methods.HasMethod(MethodHandlerKindCall, "evm.Call"))
+	require.True(methods.HasModule("contracts"))
+	require.False(methods.HasModule("contract"), "module name should match exactly")
+	require.False(methods.HasModule("evm"))
+	require.Equal("call", MethodHandlerKindCall.String())
+}
+
+func TestWithDefaultCaller(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := clienttest.NewMockRuntimeClient()
+	var lastQuery EstimateGasQuery
+	mc.SetQueryHandler(methodEstimateGas, func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		lastQuery = EstimateGasQuery{}
+		if err := cbor.Unmarshal(args, &lastQuery); err != nil {
+			return nil, err
+		}
+		return uint64(1_000), nil
+	})
+
+	tx := types.NewTransaction(nil, "test.Method", nil)
+	caller := types.CallerAddress{EthAddress: &sdkTesting.Dave.EthAddress}
+
+	cc := NewV1(mc)
+	gas, err := cc.EstimateGas(ctx, client.RoundLatest, tx)
+	require.NoError(err, "EstimateGas")
+	require.EqualValues(1_000, gas)
+	require.Nil(lastQuery.Caller, "no caller should be sent by default")
+
+	withCaller := cc.WithDefaultCaller(caller)
+	_, err = withCaller.EstimateGas(ctx, client.RoundLatest, tx)
+	require.NoError(err, "EstimateGas with default caller")
+	require.NotNil(lastQuery.Caller, "default caller should be sent")
+	require.Equal(sdkTesting.Dave.EthAddress, *lastQuery.Caller.EthAddress)
+
+	_, err = cc.EstimateGas(ctx, client.RoundLatest, tx)
+	require.NoError(err, "EstimateGas")
+	require.Nil(lastQuery.Caller, "original instance should not be modified")
+}
+
+func TestMinGasPriceForDenomination(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryResponse(methodMinGasPrice, map[types.Denomination]types.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(10),
+		"FOO":                    *quantity.NewFromUint64(2),
+	})
+	cc := NewV1(mc)
+
+	price, err := cc.MinGasPriceForDenomination(ctx, "FOO")
+	require.NoError(err, "MinGasPriceForDenomination")
+	require.EqualValues(2, price.ToBigInt().Int64())
+
+	price, err = cc.MinGasPriceForDenomination(ctx, types.NativeDenomination)
+	require.NoError(err, "MinGasPriceForDenomination")
+	require.EqualValues(10, price.ToBigInt().Int64())
+
+	_, err = cc.MinGasPriceForDenomination(ctx, "BAR")
+	require.Error(err, "MinGasPriceForDenomination should fail for unsupported denominations")
+}
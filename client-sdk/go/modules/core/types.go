@@ -1,6 +1,12 @@
 package core
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -12,3 +18,163 @@ type EstimateGasQuery struct {
 	// Tx is the unsigned transaction to estimate.
 	Tx *types.Transaction `json:"tx"`
 }
+
+// GasCosts are the gas costs charged by the core module.
+type GasCosts struct {
+	// TxByte is the cost per byte of the transaction.
+	TxByte uint64 `json:"tx_byte"`
+
+	// AuthSignature is the cost of verifying a single signature.
+	AuthSignature uint64 `json:"auth_signature"`
+	// AuthMultisigSigner is the cost of verifying a signature of each multisig signer.
+	AuthMultisigSigner uint64 `json:"auth_multisig_signer"`
+
+	// CallformatX25519Deoxysii is the cost of decrypting a call using the X25519-Deoxys-II call
+	// format.
+	CallformatX25519Deoxysii uint64 `json:"callformat_x25519_deoxysii"`
+}
+
+// Parameters are the parameters for the core module.
+type Parameters struct {
+	// MaxBatchGas is the maximum amount of gas that can be used by all transactions in a batch.
+	MaxBatchGas uint64 `json:"max_batch_gas"`
+	// MaxTxSigners is the maximum number of signers of a transaction.
+	MaxTxSigners uint32 `json:"max_tx_signers"`
+	// MaxMultisigSigners is the maximum number of signers in a multisig configuration.
+	MaxMultisigSigners uint32 `json:"max_multisig_signers"`
+	// GasCosts are the gas costs charged by the core module.
+	GasCosts GasCosts `json:"gas_costs"`
+	// MinGasPrice is the minimum gas price for each accepted fee denomination.
+	MinGasPrice map[types.Denomination]types.Quantity `json:"min_gas_price"`
+}
+
+// CheckTransaction checks the given unsigned transaction against the limits enforced by the core
+// module and returns a descriptive error in case the runtime would reject it.
+//
+// Note that the check is necessarily incomplete as the size of the transaction's signatures and
+// the gas used during execution are not known in advance.
+func (p *Parameters) CheckTransaction(tx *types.Transaction) error {
+	if n := len(tx.AuthInfo.SignerInfo); n > int(p.MaxTxSigners) {
+		return fmt.Errorf("too many transaction signers: %d exceeds %d limit", n, p.MaxTxSigners)
+	}
+	for _, si := range tx.AuthInfo.SignerInfo {
+		if si.AddressSpec.Multisig == nil {
+			continue
+		}
+		if n := len(si.AddressSpec.Multisig.Signers); n > int(p.MaxMultisigSigners) {
+			return fmt.Errorf("too many multisig signers: %d exceeds %d limit", n, p.MaxMultisigSigners)
+		}
+	}
+
+	fee := tx.AuthInfo.Fee
+	if _, ok := p.MinGasPrice[fee.Amount.Denomination]; !ok {
+		return fmt.Errorf("fees cannot be paid in denomination '%s'", fee.Amount.Denomination)
+	}
+	if fee.Gas > p.MaxBatchGas {
+		return fmt.Errorf("gas limit too high: %d exceeds %d batch gas limit", fee.Gas, p.MaxBatchGas)
+	}
+
+	size := uint64(len(cbor.Marshal(tx)))
+	sizeGas := size * p.GasCosts.TxByte
+	if sizeGas > p.MaxBatchGas {
+		return fmt.Errorf("transaction too large: %d bytes require %d gas which exceeds %d batch gas limit", size, sizeGas, p.MaxBatchGas)
+	}
+	if fee.Gas != 0 && sizeGas > fee.Gas {
+		return fmt.Errorf("gas limit too low: %d bytes require at least %d gas", size, sizeGas)
+	}
+	return nil
+}
+
+// MethodHandlerKind is the kind of a method supported by a runtime.
+type MethodHandlerKind uint8
+
+const (
+	// MethodHandlerKindCall is a callable method.
+	MethodHandlerKindCall MethodHandlerKind = 1
+	// MethodHandlerKindQuery is a query method.
+	MethodHandlerKindQuery MethodHandlerKind = 2
+)
+
+// String returns a string representation of the method kind.
+func (k MethodHandlerKind) String() string {
+	switch k {
+	case MethodHandlerKindCall:
+		return "call"
+	case MethodHandlerKindQuery:
+		return "query"
+	default:
+		return fmt.Sprintf("[unknown method kind: %d]", uint8(k))
+	}
+}
+
+// MethodHandlerInfo is information about a method supported by a runtime.
+type MethodHandlerInfo struct {
+	// Kind is the method kind.
+	Kind MethodHandlerKind `json:"kind"`
+	// Name is the fully qualified method name (e.g. "accounts.Transfer").
+	Name string `json:"name"`
+}
+
+// Module returns the name of the module that handles the method.
+func (mi *MethodHandlerInfo) Module() string {
+	return strings.SplitN(mi.Name, ".", 2)[0]
+}
+
+// MethodList is the list of methods supported by a runtime.
+type MethodList []MethodHandlerInfo
+
+func (ml MethodList) names(kind MethodHandlerKind) []string {
+	var names []string
+	for _, mi := range ml {
+		if mi.Kind == kind {
+			names = append(names, mi.Name)
+		}
+	}
+	return names
+}
+
+// Calls returns the names of all callable methods.
+func (ml MethodList) Calls() []string {
+	return ml.names(MethodHandlerKindCall)
+}
+
+// Queries returns the names of all query methods.
+func (ml MethodList) Queries() []string {
+	return ml.names(MethodHandlerKindQuery)
+}
+
+// Modules returns the sorted names of all modules that handle at least one method.
+func (ml MethodList) Modules() []string {
+	seen := make(map[string]bool)
+	var modules []string
+	for _, mi := range ml {
+		module := mi.Module()
+		if seen[module] {
+			continue
+		}
+		seen[module] = true
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// HasMethod checks whether a method with the given name and kind is supported.
+func (ml MethodList) HasMethod(kind MethodHandlerKind, name string) bool {
+	for _, mi := range ml {
+		if mi.Kind == kind && mi.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModule checks whether the given module handles at least one method.
+func (ml MethodList) HasModule(module string) bool {
+	for _, mi := range ml {
+		if mi.Module() == module {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,611 @@
+// Package abi implements a subset of the Ethereum contract ABI encoding.
+package abi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// wordSize is the size of an ABI word in bytes.
+const wordSize = 32
+
+type typeKind uint8
+
+const (
+	kindUint typeKind = iota
+	kindInt
+	kindAddress
+	kindBool
+	kindFixedBytes
+	kindBytes
+	kindString
+	kindArray
+	kindSlice
+	kindTuple
+)
+
+// abiType is a parsed ABI type.
+type abiType struct {
+	kind typeKind
+	// size is the bit size for integer types, the byte size for fixed-size byte arrays and the
+	// length for fixed-size arrays.
+	size int
+	// elem is the element type for arrays.
+	elem *abiType
+	// components are the component types for tuples.
+	components []*abiType
+}
+
+// isDynamic returns true iff the type is dynamically sized.
+func (t *abiType) isDynamic() bool {
+	switch t.kind {
+	case kindBytes, kindString, kindSlice:
+		return true
+	case kindArray:
+		return t.elem.isDynamic()
+	case kindTuple:
+		for _, c := range t.components {
+			if c.isDynamic() {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// headSize returns the number of bytes the type occupies in the head of an encoded sequence.
+func (t *abiType) headSize() int {
+	if t.isDynamic() {
+		return wordSize
+	}
+	switch t.kind {
+	case kindArray:
+		return t.size * t.elem.headSize()
+	case kindTuple:
+		var size int
+		for _, c := range t.components {
+			size += c.headSize()
+		}
+		return size
+	default:
+		return wordSize
+	}
+}
+
+// sequenceTypes returns the types of the encoded sequence of n elements of an array type or the
+// types of the components of a tuple type.
+func (t *abiType) sequenceTypes(n int) []*abiType {
+	if t.kind == kindTuple {
+		return t.components
+	}
+	ts := make([]*abiType, n)
+	for i := range ts {
+		ts[i] = t.elem
+	}
+	return ts
+}
+
+// parseType parses a canonical ABI type name (e.g. "uint256", "address[]" or "(uint256,bool)").
+func parseType(name string) (*abiType, error) {
+	switch {
+	case strings.HasSuffix(name, "]"):
+		start := strings.LastIndexByte(name, '[')
+		if start <= 0 {
+			return nil, fmt.Errorf("abi: malformed type '%s'", name)
+		}
+		elem, err := parseType(name[:start])
+		if err != nil {
+			return nil, err
+		}
+		if start == len(name)-2 {
+			return &abiType{kind: kindSlice, elem: elem}, nil
+		}
+		size, err := strconv.Atoi(name[start+1 : len(name)-1])
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("abi: malformed type '%s'", name)
+		}
+		return &abiType{kind: kindArray, size: size, elem: elem}, nil
+	case strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")"):
+		names, err := splitTypes(name[1 : len(name)-1])
+		if err != nil || len(names) == 0 {
+			return nil, fmt.Errorf("abi: malformed type '%s'", name)
+		}
+		components, err := parseTypes(names)
+		if err != nil {
+			return nil, err
+		}
+		return &abiType{kind: kindTuple, components: components}, nil
+	case name == "address":
+		return &abiType{kind: kindAddress}, nil
+	case name == "bool":
+		return &abiType{kind: kindBool}, nil
+	case name == "string":
+		return &abiType{kind: kindString}, nil
+	case name == "bytes":
+		return &abiType{kind: kindBytes}, nil
+	case name == "uint" || name == "int":
+		return parseType(name + "256")
+	case strings.HasPrefix(name, "uint"):
+		size, err := parseIntSize(name[4:])
+		if err != nil {
+			return nil, fmt.Errorf("abi: malformed type '%s': %w", name, err)
+		}
+		return &abiType{kind: kindUint, size: size}, nil
+	case strings.HasPrefix(name, "int"):
+		size, err := parseIntSize(name[3:])
+		if err != nil {
+			return nil, fmt.Errorf("abi: malformed type '%s': %w", name, err)
+		}
+		return &abiType{kind: kindInt, size: size}, nil
+	case strings.HasPrefix(name, "bytes"):
+		size, err := strconv.Atoi(name[5:])
+		if err != nil || size < 1 || size > wordSize {
+			return nil, fmt.Errorf("abi: malformed type '%s'", name)
+		}
+		return &abiType{kind: kindFixedBytes, size: size}, nil
+	default:
+		return nil, fmt.Errorf("abi: unsupported type '%s'", name)
+	}
+}
+
+func parseIntSize(s string) (int, error) {
+	size, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if size < 8 || size > 256 || size%8 != 0 {
+		return 0, fmt.Errorf("invalid integer size %d", size)
+	}
+	return size, nil
+}
+
+// splitTypes splits a comma-separated list of type names, taking nested tuples into account.
+func splitTypes(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	var (
+		names []string
+		depth int
+		start int
+	)
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				names = append(names, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return append(names, list[start:]), nil
+}
+
+func parseTypes(names []string) ([]*abiType, error) {
+	ts := make([]*abiType, 0, len(names))
+	for _, name := range names {
+		t, err := parseType(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
+
+// MethodID returns the 4-byte method identifier (selector) for the given canonical method
+// signature (e.g. "transfer(address,uint256)").
+func MethodID(signature string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+// Pack encodes the given arguments according to the given list of ABI types.
+//
+// Values for integer types may be given as *big.Int or any of the Go integer types, addresses as
+// [20]byte or a 20-byte slice, fixed-size byte arrays and bytes as byte slices. Values for arrays
+// (e.g. "address[]" or "uint256[2]") may be given as any Go slice or array of element values and
+// values for tuples (e.g. "(address,uint256)") as a slice of component values.
+func Pack(types []string, args ...interface{}) ([]byte, error) {
+	ts, err := parseTypes(types)
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) != len(args) {
+		return nil, fmt.Errorf("abi: argument count mismatch (expected: %d got: %d)", len(ts), len(args))
+	}
+	data, err := encodeSequence(ts, args)
+	if err != nil {
+		return nil, fmt.Errorf("abi: %w", err)
+	}
+	return data, nil
+}
+
+// PackMethod encodes a method call with the given canonical method signature (e.g.
+// "transfer(address,uint256)") and arguments. The encoding is prefixed with the method identifier.
+func PackMethod(signature string, args ...interface{}) ([]byte, error) {
+	types, err := signatureTypes(signature)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Pack(types, args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(MethodID(signature), data...), nil
+}
+
+// Unpack decodes the given data according to the given list of ABI types.
+//
+// Integer types are decoded as *big.Int, addresses as [20]byte, booleans as bool, strings as
+// string and byte arrays as byte slices. Arrays and tuples are decoded as []interface{}.
+func Unpack(types []string, data []byte) ([]interface{}, error) {
+	ts, err := parseTypes(types)
+	if err != nil {
+		return nil, err
+	}
+	values, err := decodeSequence(ts, data)
+	if err != nil {
+		return nil, fmt.Errorf("abi: %w", err)
+	}
+	return values, nil
+}
+
+// signatureTypes extracts the argument types from a canonical method signature.
+func signatureTypes(signature string) ([]string, error) {
+	start := strings.IndexByte(signature, '(')
+	if start <= 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("abi: malformed method signature '%s'", signature)
+	}
+	types, err := splitTypes(signature[start+1 : len(signature)-1])
+	if err != nil {
+		return nil, fmt.Errorf("abi: malformed method signature '%s': %w", signature, err)
+	}
+	return types, nil
+}
+
+func encodeSequence(ts []*abiType, values []interface{}) ([]byte, error) {
+	var headSize int
+	for _, t := range ts {
+		headSize += t.headSize()
+	}
+
+	var head, tail []byte
+	for i, t := range ts {
+		enc, err := encodeValue(t, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		if t.isDynamic() {
+			head = append(head, encodeUint64(uint64(headSize+len(tail)))...)
+			tail = append(tail, enc...)
+		} else {
+			head = append(head, enc...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func encodeValue(t *abiType, value interface{}) ([]byte, error) {
+	switch t.kind {
+	case kindUint, kindInt:
+		v, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeInt(t, v)
+	case kindAddress:
+		var addr []byte
+		switch v := value.(type) {
+		case [20]byte:
+			addr = v[:]
+		case []byte:
+			addr = v
+		default:
+			return nil, fmt.Errorf("unsupported value type for address: %T", value)
+		}
+		if len(addr) != 20 {
+			return nil, fmt.Errorf("malformed address (length: %d)", len(addr))
+		}
+		return leftPad(addr), nil
+	case kindBool:
+		v, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type for bool: %T", value)
+		}
+		if v {
+			return encodeUint64(1), nil
+		}
+		return encodeUint64(0), nil
+	case kindFixedBytes:
+		v, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type for bytes%d: %T", t.size, value)
+		}
+		if len(v) != t.size {
+			return nil, fmt.Errorf("malformed bytes%d (length: %d)", t.size, len(v))
+		}
+		return rightPad(v), nil
+	case kindBytes:
+		v, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type for bytes: %T", value)
+		}
+		return encodeDynamicBytes(v), nil
+	case kindString:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value type for string: %T", value)
+		}
+		return encodeDynamicBytes([]byte(v)), nil
+	case kindArray, kindSlice, kindTuple:
+		values, err := toSequence(value)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case t.kind == kindArray && len(values) != t.size:
+			return nil, fmt.Errorf("array length mismatch (expected: %d got: %d)", t.size, len(values))
+		case t.kind == kindTuple && len(values) != len(t.components):
+			return nil, fmt.Errorf("tuple length mismatch (expected: %d got: %d)", len(t.components), len(values))
+		}
+
+		enc, err := encodeSequence(t.sequenceTypes(len(values)), values)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == kindSlice {
+			enc = append(encodeUint64(uint64(len(values))), enc...)
+		}
+		return enc, nil
+	default:
+		return nil, fmt.Errorf("unsupported type")
+	}
+}
+
+// toSequence converts the given Go slice or array into a list of values.
+func toSequence(value interface{}) ([]interface{}, error) {
+	if values, ok := value.([]interface{}); ok {
+		return values, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return nil, fmt.Errorf("unsupported value type for array or tuple: %T", value)
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, nil
+}
+
+func encodeInt(t *abiType, v *big.Int) ([]byte, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size))
+	switch t.kind {
+	case kindUint:
+		if v.Sign() < 0 || v.Cmp(limit) >= 0 {
+			return nil, fmt.Errorf("value %s out of range for uint%d", v, t.size)
+		}
+		return leftPad(v.Bytes()), nil
+	default:
+		half := new(big.Int).Rsh(limit, 1)
+		if v.Cmp(half) >= 0 || v.Cmp(new(big.Int).Neg(half)) < 0 {
+			return nil, fmt.Errorf("value %s out of range for int%d", v, t.size)
+		}
+		if v.Sign() >= 0 {
+			return leftPad(v.Bytes()), nil
+		}
+		// Two's complement representation over the full word.
+		twos := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 8*wordSize), v)
+		return leftPad(twos.Bytes()), nil
+	}
+}
+
+func encodeDynamicBytes(data []byte) []byte {
+	enc := encodeUint64(uint64(len(data)))
+	if len(data) > 0 {
+		enc = append(enc, rightPad(data)...)
+	}
+	return enc
+}
+
+func encodeUint64(v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return leftPad(buf[:])
+}
+
+// leftPad pads the given data with zeroes on the left to a multiple of the word size.
+func leftPad(data []byte) []byte {
+	size := paddedSize(len(data))
+	out := make([]byte, size)
+	copy(out[size-len(data):], data)
+	return out
+}
+
+// rightPad pads the given data with zeroes on the right to a multiple of the word size.
+func rightPad(data []byte) []byte {
+	out := make([]byte, paddedSize(len(data)))
+	copy(out, data)
+	return out
+}
+
+func paddedSize(n int) int {
+	if n == 0 {
+		return wordSize
+	}
+	return (n + wordSize - 1) / wordSize * wordSize
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("nil integer value")
+		}
+		return v, nil
+	case big.Int:
+		return &v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type for integer: %T", value)
+	}
+}
+
+func decodeSequence(ts []*abiType, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(ts))
+	var offset int
+	for i, t := range ts {
+		if len(data) < offset+t.headSize() {
+			return nil, fmt.Errorf("value %d: data too short", i)
+		}
+
+		var (
+			value interface{}
+			err   error
+		)
+		if t.isDynamic() {
+			var tailOffset int
+			tailOffset, err = decodeOffset(data[offset:offset+wordSize], len(data))
+			if err == nil {
+				value, err = decodeValue(t, data[tailOffset:])
+			}
+		} else {
+			value, err = decodeValue(t, data[offset:offset+t.headSize()])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		values = append(values, value)
+		offset += t.headSize()
+	}
+	return values, nil
+}
+
+func decodeValue(t *abiType, data []byte) (interface{}, error) {
+	switch t.kind {
+	case kindArray, kindTuple:
+		n := t.size
+		if t.kind == kindTuple {
+			n = len(t.components)
+		}
+		return decodeSequence(t.sequenceTypes(n), data)
+	case kindSlice:
+		if len(data) < wordSize {
+			return nil, fmt.Errorf("data too short")
+		}
+		// Make sure the length is bounded by the available data before allocating anything.
+		n, err := decodeOffset(data[:wordSize], (len(data)-wordSize)/t.elem.headSize())
+		if err != nil {
+			return nil, err
+		}
+		return decodeSequence(t.sequenceTypes(n), data[wordSize:])
+	}
+
+	if len(data) < wordSize {
+		return nil, fmt.Errorf("data too short")
+	}
+	word := data[:wordSize]
+
+	switch t.kind {
+	case kindUint:
+		v := new(big.Int).SetBytes(word)
+		if v.BitLen() > t.size {
+			return nil, fmt.Errorf("value out of range for uint%d", t.size)
+		}
+		return v, nil
+	case kindInt:
+		v := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 8*wordSize))
+		}
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.size-1))
+		if v.Cmp(limit) >= 0 || v.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("value out of range for int%d", t.size)
+		}
+		return v, nil
+	case kindAddress:
+		if !isZero(word[:wordSize-20]) {
+			return nil, fmt.Errorf("malformed address")
+		}
+		var addr [20]byte
+		copy(addr[:], word[wordSize-20:])
+		return addr, nil
+	case kindBool:
+		if !isZero(word[:wordSize-1]) || word[wordSize-1] > 1 {
+			return nil, fmt.Errorf("malformed bool")
+		}
+		return word[wordSize-1] == 1, nil
+	case kindFixedBytes:
+		return append([]byte{}, word[:t.size]...), nil
+	case kindBytes, kindString:
+		length, err := decodeOffset(word, len(data)-wordSize)
+		if err != nil {
+			return nil, err
+		}
+		raw := append([]byte{}, data[wordSize:wordSize+length]...)
+		if t.kind == kindString {
+			return string(raw), nil
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("unsupported type")
+	}
+}
+
+// decodeOffset decodes a word containing an offset or length and makes sure that it does not
+// exceed the given limit.
+func decodeOffset(word []byte, limit int) (int, error) {
+	v := new(big.Int).SetBytes(word)
+	if !v.IsInt64() || v.Int64() > int64(limit) {
+		return 0, fmt.Errorf("offset or length out of bounds")
+	}
+	return int(v.Int64()), nil
+}
+
+func isZero(data []byte) bool {
+	return bytes.Count(data, []byte{0}) == len(data)
+}
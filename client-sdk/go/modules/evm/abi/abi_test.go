@@ -0,0 +1,233 @@
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMethodID(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		signature  string
+		expectedID string
+	}{
+		{"name()", "06fdde03"},
+		{"balanceOf(address)", "70a08231"},
+		{"transfer(address,uint256)", "a9059cbb"},
+	} {
+		require.Equal(tc.expectedID, hex.EncodeToString(MethodID(tc.signature)), tc.signature)
+	}
+}
+
+func TestPackMethod(t *testing.T) {
+	require := require.New(t)
+
+	var to [20]byte
+	to[18] = 0x01
+	to[19] = 0x23
+	data, err := PackMethod("transfer(address,uint256)", to, big.NewInt(0x42))
+	require.NoError(err, "PackMethod")
+	require.Equal("a9059cbb"+strings.Repeat("0", 64-3)+"123"+strings.Repeat("0", 64-2)+"42", hex.EncodeToString(data))
+
+	data, err = PackMethod("name()")
+	require.NoError(err, "PackMethod without arguments")
+	require.Equal("06fdde03", hex.EncodeToString(data))
+
+	_, err = PackMethod("transfer(address,uint256)", to)
+	require.Error(err, "PackMethod should fail with missing arguments")
+	_, err = PackMethod("transfer")
+	require.Error(err, "PackMethod should fail with malformed signature")
+}
+
+func TestPackUnpack(t *testing.T) {
+	require := require.New(t)
+
+	var addr [20]byte
+	addr[0] = 0xff
+	addr[19] = 0x01
+
+	for _, tc := range []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"uint256"}, []interface{}{big.NewInt(1234)}},
+		{[]string{"uint8"}, []interface{}{big.NewInt(255)}},
+		{[]string{"int64"}, []interface{}{big.NewInt(-1)}},
+		{[]string{"int256"}, []interface{}{big.NewInt(-1234567)}},
+		{[]string{"address"}, []interface{}{addr}},
+		{[]string{"bool", "bool"}, []interface{}{true, false}},
+		{[]string{"bytes4"}, []interface{}{[]byte{1, 2, 3, 4}}},
+		{[]string{"string"}, []interface{}{"Test"}},
+		{[]string{"string"}, []interface{}{""}},
+		{[]string{"bytes"}, []interface{}{[]byte(strings.Repeat("x", 70))}},
+		{[]string{"uint256", "string", "address", "bytes"}, []interface{}{big.NewInt(42), "hello", addr, []byte{0xde, 0xad}}},
+	} {
+		enc, err := Pack(tc.types, tc.values...)
+		require.NoError(err, "Pack %v", tc.types)
+		require.Zero(len(enc)%wordSize, "encoding should be word-aligned")
+
+		dec, err := Unpack(tc.types, enc)
+		require.NoError(err, "Unpack %v", tc.types)
+		require.EqualValues(tc.values, dec, "encoding should round-trip")
+	}
+}
+
+func TestPackInvalid(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"uint7"}, []interface{}{1}},
+		{[]string{"bytes33"}, []interface{}{[]byte{}}},
+		{[]string{"foo"}, []interface{}{1}},
+		{[]string{"uint8"}, []interface{}{256}},
+		{[]string{"uint256"}, []interface{}{-1}},
+		{[]string{"int8"}, []interface{}{128}},
+		{[]string{"int8"}, []interface{}{-129}},
+		{[]string{"address"}, []interface{}{[]byte{1, 2, 3}}},
+		{[]string{"bool"}, []interface{}{1}},
+		{[]string{"bytes2"}, []interface{}{[]byte{1}}},
+		{[]string{"string"}, []interface{}{[]byte("foo")}},
+	} {
+		_, err := Pack(tc.types, tc.values...)
+		require.Error(err, "Pack %v should fail", tc.types)
+	}
+}
+
+func TestUnpackString(t *testing.T) {
+	require := require.New(t)
+
+	// Result of calling name() on a contract returning "Test".
+	raw, _ := hex.DecodeString(
+		strings.Repeat("0", 62) + "20" +
+			strings.Repeat("0", 63) + "4" +
+			"54657374" + strings.Repeat("0", 56),
+	)
+	dec, err := Unpack([]string{"string"}, raw)
+	require.NoError(err, "Unpack")
+	require.Equal([]interface{}{"Test"}, dec)
+
+	_, err = Unpack([]string{"string"}, raw[:64])
+	require.Error(err, "Unpack should fail on truncated data")
+	_, err = Unpack([]string{"uint256", "uint256"}, raw[:32])
+	require.Error(err, "Unpack should fail on missing values")
+}
+
+func TestPackArraysAndTuples(t *testing.T) {
+	require := require.New(t)
+
+	word := func(v string) string {
+		return strings.Repeat("0", 64-len(v)) + v
+	}
+	text := func(v string) string {
+		enc := hex.EncodeToString([]byte(v))
+		return enc + strings.Repeat("0", 64-len(enc))
+	}
+
+	// Test vectors from the Solidity ABI specification.
+	for _, tc := range []struct {
+		signature string
+		args      []interface{}
+		expected  string
+	}{
+		{
+			"bar(bytes3[2])",
+			[]interface{}{[][]byte{[]byte("abc"), []byte("def")}},
+			"fce353f6" + text("abc") + text("def"),
+		},
+		{
+			"sam(bytes,bool,uint256[])",
+			[]interface{}{[]byte("dave"), true, []uint64{1, 2, 3}},
+			"a5643bf2" + word("60") + word("1") + word("a0") + word("4") + text("dave") + word("3") + word("1") + word("2") + word("3"),
+		},
+		{
+			"f(uint256,uint32[],bytes10,bytes)",
+			[]interface{}{0x123, []interface{}{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!")},
+			"8be65246" + word("123") + word("80") + text("1234567890") + word("e0") + word("2") + word("456") + word("789") + word("d") + text("Hello, world!"),
+		},
+		{
+			"g(uint256[][],string[])",
+			[]interface{}{[][]uint64{{1, 2}, {3}}, []string{"one", "two", "three"}},
+			"2289b18c" + word("40") + word("140") +
+				word("2") + word("40") + word("a0") + word("2") + word("1") + word("2") + word("1") + word("3") +
+				word("3") + word("60") + word("a0") + word("e0") + word("3") + text("one") + word("3") + text("two") + word("5") + text("three"),
+		},
+		{
+			"h((uint256,string),bool)",
+			[]interface{}{[]interface{}{1, "hi"}, true},
+			hex.EncodeToString(MethodID("h((uint256,string),bool)")) + word("40") + word("1") + word("1") + word("40") + word("2") + text("hi"),
+		},
+		{
+			"k((uint256,bool)[2])",
+			[]interface{}{[]interface{}{[]interface{}{1, true}, []interface{}{2, false}}},
+			hex.EncodeToString(MethodID("k((uint256,bool)[2])")) + word("1") + word("1") + word("2") + word("0"),
+		},
+	} {
+		data, err := PackMethod(tc.signature, tc.args...)
+		require.NoError(err, "PackMethod %s", tc.signature)
+		require.Equal(tc.expected, hex.EncodeToString(data), tc.signature)
+	}
+
+	var addr [20]byte
+	addr[19] = 0x42
+	for _, tc := range []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"uint256[]"}, []interface{}{[]interface{}{}}},
+		{[]string{"address[]", "uint256[]"}, []interface{}{
+			[]interface{}{addr, addr},
+			[]interface{}{big.NewInt(1), big.NewInt(2)},
+		}},
+		{[]string{"string[2]"}, []interface{}{[]interface{}{"a", "b"}}},
+		{[]string{"uint8[2][]"}, []interface{}{[]interface{}{
+			[]interface{}{big.NewInt(1), big.NewInt(2)},
+			[]interface{}{big.NewInt(3), big.NewInt(4)},
+		}}},
+		{[]string{"(address,bytes)[]", "bool"}, []interface{}{
+			[]interface{}{
+				[]interface{}{addr, []byte{1, 2, 3}},
+				[]interface{}{addr, []byte{}},
+			},
+			true,
+		}},
+		{[]string{"(uint256,(bool,string[]))"}, []interface{}{
+			[]interface{}{big.NewInt(7), []interface{}{false, []interface{}{"x", "yz"}}},
+		}},
+	} {
+		enc, err := Pack(tc.types, tc.values...)
+		require.NoError(err, "Pack %v", tc.types)
+
+		dec, err := Unpack(tc.types, enc)
+		require.NoError(err, "Unpack %v", tc.types)
+		require.EqualValues(tc.values, dec, "encoding should round-trip")
+	}
+
+	for _, tc := range []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"uint256[2]"}, []interface{}{[]interface{}{1}}},
+		{[]string{"uint256[]"}, []interface{}{1}},
+		{[]string{"(uint256,bool)"}, []interface{}{[]interface{}{1}}},
+		{[]string{"uint256[0]"}, []interface{}{[]interface{}{}}},
+		{[]string{"()"}, []interface{}{[]interface{}{}}},
+		{[]string{"(uint256"}, []interface{}{[]interface{}{1}}},
+		{[]string{"[]"}, []interface{}{[]interface{}{}}},
+	} {
+		_, err := Pack(tc.types, tc.values...)
+		require.Error(err, "Pack %v should fail", tc.types)
+	}
+
+	// A length exceeding the available data should be rejected.
+	raw, _ := hex.DecodeString(word("20") + word("ffff") + word("1"))
+	_, err := Unpack([]string{"uint256[]"}, raw)
+	require.Error(err, "Unpack should fail on truncated arrays")
+}
@@ -0,0 +1,38 @@
+package evm
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ContractAddress derives the address of a contract created via CREATE by the given sender when
+// the sender's nonce equals the given nonce.
+func ContractAddress(sender []byte, nonce uint64) []byte {
+	// The address is keccak256(rlp([sender, nonce]))[12:].
+	var encNonce []byte
+	switch {
+	case nonce == 0:
+		encNonce = []byte{0x80}
+	case nonce < 0x80:
+		encNonce = []byte{byte(nonce)}
+	default:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], nonce)
+		i := 0
+		for buf[i] == 0 {
+			i++
+		}
+		encNonce = append([]byte{0x80 + byte(8-i)}, buf[i:]...)
+	}
+
+	payloadLen := 1 + len(sender) + len(encNonce)
+	data := make([]byte, 0, 1+payloadLen)
+	data = append(data, 0xc0+byte(payloadLen), 0x80+byte(len(sender)))
+	data = append(data, sender...)
+	data = append(data, encNonce...)
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)[12:]
+}
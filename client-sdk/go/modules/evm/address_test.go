@@ -0,0 +1,25 @@
+package evm
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractAddress(t *testing.T) {
+	require := require.New(t)
+
+	sender, _ := hex.DecodeString("6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	for _, tc := range []struct {
+		nonce           uint64
+		expectedAddress string
+	}{
+		{0, "cd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{1, "343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+		{2, "f778b86fa74e846c4f0a1fbd1335fe81c00a0c91"},
+		{3, "fffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c"},
+	} {
+		require.Equal(tc.expectedAddress, hex.EncodeToString(ContractAddress(sender, tc.nonce)), "nonce %d", tc.nonce)
+	}
+}
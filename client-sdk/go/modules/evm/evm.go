@@ -1,12 +1,16 @@
 package evm
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"math/big"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm/abi"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
@@ -38,22 +42,90 @@ type V1 interface {
 	// high enough to cover the EVM gas price multiplied by the EVM gas limit.
 	Call(address []byte, value []byte, data []byte) *client.TransactionBuilder
 
+	// Deploy generates an EVM CREATE transaction deploying the given contract bytecode with the
+	// given constructor arguments ABI-encoded (see EncodeConstructorArgs) and appended to it.
+	// The same gas and fee considerations as for Create apply.
+	Deploy(value []byte, bytecode []byte, argTypes []string, args ...interface{}) (*client.TransactionBuilder, error)
+
+	// SendRawTransaction submits the given RLP-encoded signed Ethereum transaction to the EVM
+	// module and waits for its result. This allows transactions signed by existing Ethereum
+	// wallets and libraries to be submitted directly.
+	//
+	// In case submission fails, the returned error includes the transaction's sender and nonce.
+	SendRawTransaction(ctx context.Context, rawEthTx []byte) (cbor.RawMessage, error)
+
 	// Storage queries the EVM storage.
 	Storage(ctx context.Context, round uint64, address []byte, index []byte) ([]byte, error)
 
 	// Code queries the EVM code storage.
 	Code(ctx context.Context, round uint64, address []byte) ([]byte, error)
 
+	// IsContract checks whether the given address is a contract, i.e. whether there is code
+	// stored at the address. Addresses without code (e.g., externally owned accounts or unused
+	// addresses) are not contracts.
+	IsContract(ctx context.Context, round uint64, address [20]byte) (bool, error)
+
 	// Balance queries the EVM account balance.
 	Balance(ctx context.Context, round uint64, address []byte) (*types.Quantity, error)
 
+	// Nonce queries the nonce of the given EVM account.
+	//
+	// The nonce of the sender before a CREATE transaction is submitted can be passed to
+	// ContractAddress to derive the address of the created contract.
+	Nonce(ctx context.Context, round uint64, address []byte) (uint64, error)
+
 	// SimulateCall simulates an EVM CALL.
 	SimulateCall(ctx context.Context, round uint64, gasPrice []byte, gasLimit uint64, caller []byte, address []byte, value []byte, data []byte) ([]byte, error)
 
+	// SuggestGasPrice returns a recommended gas price for EVM transactions, similar to Ethereum's
+	// eth_gasPrice. It is the median gas price (in the native denomination) of transactions in
+	// the most recent rounds up to the given round, but never less than the runtime's minimum gas
+	// price.
+	SuggestGasPrice(ctx context.Context, round uint64) (*big.Int, error)
+
+	// SuggestFees returns a recommended base fee and priority fee (tip) per gas for EVM
+	// transactions, similar to what post-London Ethereum tooling expects. Since the runtime has
+	// no base fee that adjusts to demand, the base fee is the runtime's minimum gas price and the
+	// tip is the difference between the price returned by SuggestGasPrice and the base fee.
+	//
+	// The fees can be applied to a transaction via SetFees.
+	SuggestFees(ctx context.Context, round uint64) (baseFee, tip *big.Int, err error)
+
 	// GetEvents returns events emitted by the EVM module.
 	GetEvents(ctx context.Context, round uint64) ([]*Event, error)
+
+	// FilterLogs returns logs emitted in rounds between fromRound and toRound (inclusive) that
+	// match the given filter. RoundLatest can be used as toRound. At most MaxFilterLogsRange
+	// rounds can be scanned in a single call.
+	//
+	// A log matches in case it was emitted by one of the given addresses (or addresses is empty)
+	// and each non-nil entry of topics equals the log's topic at the same position.
+	FilterLogs(ctx context.Context, fromRound, toRound uint64, addresses [][]byte, topics [][]byte) ([]*Log, error)
+
+	// GetReceipt returns the receipt of the EVM transaction with the given hash, similar to
+	// Ethereum's eth_getTransactionReceipt. The hash can either be the SDK transaction hash (see
+	// types.UnverifiedTransaction.Hash) or, for transactions submitted via SendRawTransaction, the
+	// Ethereum transaction hash.
+	//
+	// The runtime does not index transactions by hash, so only the most recent
+	// MaxReceiptSearchRounds rounds are scanned, starting at the latest one. Use
+	// GetReceiptInRound in case the round is known (e.g., as reported by SubmitTxMeta). In case
+	// the transaction is not found, ErrReceiptNotFound is returned. Unlike Ethereum receipts, the
+	// receipt does not include the amount of gas used.
+	GetReceipt(ctx context.Context, txHash []byte) (*Receipt, error)
+
+	// GetReceiptInRound returns the receipt of the EVM transaction with the given hash executed in
+	// the given round (see GetReceipt). In case the transaction is not found in the given round,
+	// ErrReceiptNotFound is returned.
+	GetReceiptInRound(ctx context.Context, round uint64, txHash []byte) (*Receipt, error)
 }
 
+// MaxFilterLogsRange is the maximum number of rounds that can be scanned by FilterLogs.
+const MaxFilterLogsRange = 10_000
+
+// MaxReceiptSearchRounds is the maximum number of rounds scanned by GetReceipt.
+const MaxReceiptSearchRounds = 100
+
 type v1 struct {
 	rtc client.RuntimeClient
 }
@@ -66,6 +138,29 @@ func (a *v1) Create(value []byte, initCode []byte) *client.TransactionBuilder {
 	})
 }
 
+// Implements V1.
+func (a *v1) Deploy(value []byte, bytecode []byte, argTypes []string, args ...interface{}) (*client.TransactionBuilder, error) {
+	encArgs, err := EncodeConstructorArgs(argTypes, args...)
+	if err != nil {
+		return nil, err
+	}
+	initCode := make([]byte, 0, len(bytecode)+len(encArgs))
+	initCode = append(initCode, bytecode...)
+	initCode = append(initCode, encArgs...)
+	return a.Create(value, initCode), nil
+}
+
+// EncodeConstructorArgs ABI-encodes the given contract constructor arguments. The result needs
+// to be appended to the contract's deployment bytecode to form the init code of a CREATE
+// transaction.
+func EncodeConstructorArgs(argTypes []string, args ...interface{}) ([]byte, error) {
+	data, err := abi.Pack(argTypes, args...)
+	if err != nil {
+		return nil, fmt.Errorf("evm: malformed constructor arguments: %w", err)
+	}
+	return data, nil
+}
+
 // Implements V1.
 func (a *v1) Call(address []byte, value []byte, data []byte) *client.TransactionBuilder {
 	return client.NewTransactionBuilder(a.rtc, methodCall, &Call{
@@ -100,6 +195,15 @@ func (a *v1) Code(ctx context.Context, round uint64, address []byte) ([]byte, er
 	return res, nil
 }
 
+// Implements V1.
+func (a *v1) IsContract(ctx context.Context, round uint64, address [20]byte) (bool, error) {
+	code, err := a.Code(ctx, round, address[:])
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
 // Implements V1.
 func (a *v1) Balance(ctx context.Context, round uint64, address []byte) (*types.Quantity, error) {
 	var res types.Quantity
@@ -112,6 +216,14 @@ func (a *v1) Balance(ctx context.Context, round uint64, address []byte) (*types.
 	return &res, nil
 }
 
+// Implements V1.
+func (a *v1) Nonce(ctx context.Context, round uint64, address []byte) (uint64, error) {
+	if len(address) != 20 {
+		return 0, fmt.Errorf("malformed address (expected 20 bytes, got %d)", len(address))
+	}
+	return accounts.NewV1(a.rtc).Nonce(ctx, round, types.NewAddressFromEth(address))
+}
+
 // Implements V1.
 func (a *v1) SimulateCall(ctx context.Context, round uint64, gasPrice []byte, gasLimit uint64, caller []byte, address []byte, value []byte, data []byte) ([]byte, error) {
 	var res []byte
@@ -151,9 +263,69 @@ func (a *v1) GetEvents(ctx context.Context, round uint64) ([]*Event, error) {
 	return evs, nil
 }
 
+// Implements V1.
+func (a *v1) FilterLogs(ctx context.Context, fromRound, toRound uint64, addresses [][]byte, topics [][]byte) ([]*Log, error) {
+	if toRound == client.RoundLatest {
+		blk, err := a.rtc.GetBlock(ctx, client.RoundLatest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+		}
+		toRound = blk.Header.Round
+	}
+	if fromRound > toRound {
+		return nil, fmt.Errorf("invalid round range: %d > %d", fromRound, toRound)
+	}
+	if toRound-fromRound >= MaxFilterLogsRange {
+		return nil, fmt.Errorf("round range too large: %d rounds exceeds %d limit", toRound-fromRound+1, MaxFilterLogsRange)
+	}
+
+	var logs []*Log
+	for round := fromRound; round <= toRound; round++ {
+		evs, err := a.GetEvents(ctx, round)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch events for round %d: %w", round, err)
+		}
+		for _, ev := range evs {
+			if !logMatches(ev, addresses, topics) {
+				continue
+			}
+			logs = append(logs, &Log{
+				Round: round,
+				Event: *ev,
+			})
+		}
+	}
+	return logs, nil
+}
+
+// logMatches checks whether the given log event matches the filter.
+func logMatches(ev *Event, addresses [][]byte, topics [][]byte) bool {
+	if len(addresses) > 0 {
+		var found bool
+		for _, addr := range addresses {
+			if bytes.Equal(addr, ev.Address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for i, topic := range topics {
+		if topic == nil {
+			continue
+		}
+		if i >= len(ev.Topics) || !bytes.Equal(topic, ev.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // Implements client.EventDecoder.
 func (a *v1) DecodeEvent(event *types.Event) (client.DecodedEvent, error) {
-	if event.Module != ModuleName && event.Code != 1 {
+	if event.Module != ModuleName || event.Code != 1 {
 		return nil, nil
 	}
 	var ev *Event
@@ -167,3 +339,7 @@ func (a *v1) DecodeEvent(event *types.Event) (client.DecodedEvent, error) {
 func NewV1(rtc client.RuntimeClient) V1 {
 	return &v1{rtc: rtc}
 }
+
+func init() {
+	client.RegisterEventDecoder(ModuleName, &v1{})
+}
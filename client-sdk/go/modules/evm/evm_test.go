@@ -0,0 +1,170 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestLogMatches(t *testing.T) {
+	require := require.New(t)
+
+	ev := &Event{
+		Address: []byte{0x01},
+		Topics:  [][]byte{{0xaa}, {0xbb}},
+	}
+
+	for _, tc := range []struct {
+		addresses [][]byte
+		topics    [][]byte
+		matches   bool
+	}{
+		{nil, nil, true},
+		{[][]byte{{0x01}}, nil, true},
+		{[][]byte{{0x02}, {0x01}}, nil, true},
+		{[][]byte{{0x02}}, nil, false},
+		{nil, [][]byte{{0xaa}}, true},
+		{nil, [][]byte{nil, {0xbb}}, true},
+		{nil, [][]byte{{0xbb}}, false},
+		{nil, [][]byte{{0xaa}, {0xbb}, {0xcc}}, false},
+		{[][]byte{{0x01}}, [][]byte{{0xaa}, {0xbb}}, true},
+	} {
+		require.Equal(tc.matches, logMatches(ev, tc.addresses, tc.topics), "addresses: %x topics: %x", tc.addresses, tc.topics)
+	}
+}
+
+func TestSuggestGasPrice(t *testing.T) {
+	require := require.New(t)
+
+	prices := func(ps ...int64) []*big.Int {
+		var res []*big.Int
+		for _, p := range ps {
+			res = append(res, big.NewInt(p))
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		minPrice int64
+		prices   []*big.Int
+		expected int64
+	}{
+		{0, nil, 0},
+		{5, nil, 5},
+		{0, prices(3, 1, 2), 2},
+		{0, prices(10, 1, 4, 2), 4},
+		{5, prices(3, 1, 2), 5},
+	} {
+		require.EqualValues(tc.expected, suggestGasPrice(big.NewInt(tc.minPrice), tc.prices).Int64())
+	}
+}
+
+func TestDeploy(t *testing.T) {
+	require := require.New(t)
+
+	bytecode := []byte{0x60, 0x80, 0x60, 0x40}
+	tb, err := NewV1(nil).Deploy(nil, bytecode, []string{"string", "uint8"}, "Token", 18)
+	require.NoError(err, "Deploy")
+
+	var body Create
+	err = cbor.Unmarshal(tb.GetTransaction().Call.Body, &body)
+	require.NoError(err, "malformed call body")
+	// ABI encoding of ("Token", 18) as (string, uint8).
+	encArgs, _ := hex.DecodeString(
+		"0000000000000000000000000000000000000000000000000000000000000040" + // Offset of the string.
+			"0000000000000000000000000000000000000000000000000000000000000012" + // 18.
+			"0000000000000000000000000000000000000000000000000000000000000005" + // String length.
+			"546f6b656e000000000000000000000000000000000000000000000000000000", // "Token".
+	)
+	require.Equal(append(bytecode, encArgs...), body.InitCode, "constructor arguments should be appended to the bytecode")
+
+	tb, err = NewV1(nil).Deploy(nil, bytecode, nil)
+	require.NoError(err, "Deploy without constructor arguments")
+	err = cbor.Unmarshal(tb.GetTransaction().Call.Body, &body)
+	require.NoError(err, "malformed call body")
+	require.Equal(bytecode, body.InitCode)
+
+	_, err = NewV1(nil).Deploy(nil, bytecode, []string{"uint8"})
+	require.Error(err, "Deploy should fail with missing constructor arguments")
+}
+
+func TestSuggestFees(t *testing.T) {
+	require := require.New(t)
+
+	newTx := func(amount, gas uint64) *client.TransactionWithResults {
+		tx := types.NewTransaction(&types.Fee{
+			Amount: types.NewBaseUnits(*quantity.NewFromUint64(amount), types.NativeDenomination),
+			Gas:    gas,
+		}, methodCall, nil)
+		return &client.TransactionWithResults{Tx: types.UnverifiedTransaction{Body: cbor.Marshal(tx)}}
+	}
+
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryResponse("core.MinGasPrice", map[types.Denomination]types.Quantity{
+		types.NativeDenomination: *quantity.NewFromUint64(10),
+	})
+	mc.SetTransactions(1, []*client.TransactionWithResults{newTx(1500, 100)})
+	baseFee, tip, err := NewV1(mc).SuggestFees(context.Background(), 1)
+	require.NoError(err, "SuggestFees")
+	require.EqualValues(10, baseFee.Int64())
+	require.EqualValues(5, tip.Int64())
+
+	mc.SetTransactions(1, nil)
+	baseFee, tip, err = NewV1(mc).SuggestFees(context.Background(), 1)
+	require.NoError(err, "SuggestFees")
+	require.EqualValues(10, baseFee.Int64())
+	require.EqualValues(0, tip.Int64(), "tip should be zero without sampled transactions")
+
+	require.EqualValues(23, MaxFeePerGas(big.NewInt(10), big.NewInt(3)).Int64())
+
+	tb := NewV1(mc).Call(nil, nil, nil).SetFeeGas(100)
+	require.NoError(SetFees(tb, big.NewInt(10), big.NewInt(3)), "SetFees")
+	fee := tb.GetTransaction().AuthInfo.Fee
+	require.Equal("2300", fee.Amount.Amount.String())
+	require.True(fee.Amount.Denomination.IsNative())
+	require.Error(SetFees(tb, big.NewInt(-10), big.NewInt(3)), "SetFees should reject negative gas prices")
+}
+
+func TestIsContract(t *testing.T) {
+	require := require.New(t)
+
+	contract := [20]byte{0x01}
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetQueryHandler(methodCode, func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		var q CodeQuery
+		if err := cbor.Unmarshal(args, &q); err != nil {
+			return nil, err
+		}
+		if bytes.Equal(q.Address, contract[:]) {
+			return []byte{0x60, 0x80}, nil
+		}
+		return []byte{}, nil
+	})
+	e := NewV1(mc)
+
+	isContract, err := e.IsContract(context.Background(), client.RoundLatest, contract)
+	require.NoError(err, "IsContract")
+	require.True(isContract, "address with code should be a contract")
+
+	isContract, err = e.IsContract(context.Background(), client.RoundLatest, [20]byte{0x02})
+	require.NoError(err, "IsContract")
+	require.False(isContract, "address without code should not be a contract")
+
+	mc.SetQueryHandler(methodCode, func(uint64, cbor.RawMessage) (interface{}, error) {
+		return nil, fmt.Errorf("query failed")
+	})
+	_, err = e.IsContract(context.Background(), client.RoundLatest, contract)
+	require.Error(err, "IsContract should propagate query errors")
+}
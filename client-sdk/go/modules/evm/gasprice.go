@@ -0,0 +1,116 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// gasPriceSampleRounds is the number of most recent rounds sampled by SuggestGasPrice.
+const gasPriceSampleRounds = 20
+
+// Implements V1.
+func (a *v1) SuggestGasPrice(ctx context.Context, round uint64) (*big.Int, error) {
+	_, price, err := a.sampleGasPrice(ctx, round)
+	return price, err
+}
+
+// Implements V1.
+func (a *v1) SuggestFees(ctx context.Context, round uint64) (*big.Int, *big.Int, error) {
+	// The runtime has no base fee that adjusts to demand, so the minimum gas price is used.
+	baseFee, price, err := a.sampleGasPrice(ctx, round)
+	if err != nil {
+		return nil, nil, err
+	}
+	return baseFee, new(big.Int).Sub(price, baseFee), nil
+}
+
+// MaxFeePerGas returns the maximum gas price for the given base fee and tip, following the common
+// EIP-1559 practice of leaving room for the base fee to double: 2 * baseFee + tip.
+func MaxFeePerGas(baseFee, tip *big.Int) *big.Int {
+	maxFee := new(big.Int).Lsh(baseFee, 1)
+	return maxFee.Add(maxFee, tip)
+}
+
+// SetFees configures the fee of the given transaction to be paid in the native denomination at
+// the gas price returned by MaxFeePerGas for the given base fee and tip (see SuggestFees).
+//
+// Note that, unlike in Ethereum, the runtime charges the full fee (the gas limit multiplied by
+// the gas price) and does not refund the difference to the base fee. This method must be called
+// after the gas limit has been configured via SetFeeGas.
+func SetFees(tb *client.TransactionBuilder, baseFee, tip *big.Int) error {
+	var gasPrice types.Quantity
+	if err := gasPrice.FromBigInt(MaxFeePerGas(baseFee, tip)); err != nil {
+		return fmt.Errorf("malformed gas price: %w", err)
+	}
+	tb.SetFeeDenomination(types.NativeDenomination)
+	return tb.SetFeeGasPrice(gasPrice)
+}
+
+// sampleGasPrice returns the runtime's minimum gas price and the recommended gas price (see
+// SuggestGasPrice) in the native denomination.
+func (a *v1) sampleGasPrice(ctx context.Context, round uint64) (*big.Int, *big.Int, error) {
+	minPrice, err := core.NewV1(a.rtc).MinGasPriceForDenomination(ctx, types.NativeDenomination)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query minimum gas price: %w", err)
+	}
+
+	if round == client.RoundLatest {
+		blk, berr := a.rtc.GetBlock(ctx, client.RoundLatest)
+		if berr != nil {
+			return nil, nil, fmt.Errorf("failed to fetch latest block: %w", berr)
+		}
+		round = blk.Header.Round
+	}
+	fromRound := uint64(0)
+	if round >= gasPriceSampleRounds {
+		fromRound = round - gasPriceSampleRounds + 1
+	}
+
+	var prices []*big.Int
+	for r := fromRound; r <= round; r++ {
+		txs, terr := a.rtc.GetTransactions(ctx, r)
+		if terr != nil {
+			return nil, nil, fmt.Errorf("failed to fetch transactions for round %d: %w", r, terr)
+		}
+		for _, utx := range txs {
+			// Transactions in a block have already been verified.
+			var tx types.Transaction
+			if err = cbor.Unmarshal(utx.Body, &tx); err != nil {
+				// Not an SDK transaction (e.g. an Ethereum transaction).
+				continue
+			}
+			fee := tx.AuthInfo.Fee
+			if !fee.Amount.Denomination.IsNative() || fee.Gas == 0 {
+				continue
+			}
+			prices = append(prices, new(big.Int).Div(fee.Amount.Amount.ToBigInt(), new(big.Int).SetUint64(fee.Gas)))
+		}
+	}
+
+	return minPrice.ToBigInt(), suggestGasPrice(minPrice.ToBigInt(), prices), nil
+}
+
+// suggestGasPrice returns the median of the given sampled gas prices, but at least the given
+// minimum gas price.
+func suggestGasPrice(minPrice *big.Int, prices []*big.Int) *big.Int {
+	if len(prices) == 0 {
+		return minPrice
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+	median := prices[len(prices)/2]
+	if median.Cmp(minPrice) < 0 {
+		return minPrice
+	}
+	return median
+}
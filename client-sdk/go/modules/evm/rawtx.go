@@ -0,0 +1,299 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ethereumTxScheme is the module-controlled transaction decoding scheme handled by the EVM
+// module for signed Ethereum transactions.
+const ethereumTxScheme = "evm.ethereum.v0"
+
+// Ethereum transaction types as defined by EIP-2718.
+const (
+	ethTxTypeAccessList = 0x01
+	ethTxTypeDynamicFee = 0x02
+)
+
+// RawTransactionInfo is information about a signed Ethereum transaction.
+type RawTransactionInfo struct {
+	// ChainID is the chain ID the transaction was signed for. It is nil for legacy transactions
+	// signed without replay protection (EIP-155).
+	ChainID *big.Int
+	// Nonce is the nonce of the sender.
+	Nonce uint64
+	// Sender is the Ethereum address of the sender derived from the signature.
+	Sender []byte
+	// To is the Ethereum address of the recipient. It is nil for contract creations.
+	To []byte
+	// Hash is the Ethereum transaction hash.
+	Hash []byte
+}
+
+// DecodeRawTransaction decodes the given RLP-encoded signed Ethereum transaction (either a legacy,
+// an EIP-2930 or an EIP-1559 transaction) and recovers its sender.
+func DecodeRawTransaction(raw []byte) (*RawTransactionInfo, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty transaction")
+	}
+
+	var (
+		txType byte
+		fields []rlpItem
+	)
+	switch {
+	case raw[0] >= 0xc0:
+		// Legacy transaction.
+		item, err := rlpDecodeSingle(raw)
+		if err != nil {
+			return nil, err
+		}
+		fields = item.list
+		if !item.isList || len(fields) != 9 {
+			return nil, fmt.Errorf("malformed legacy transaction")
+		}
+	case raw[0] == ethTxTypeAccessList || raw[0] == ethTxTypeDynamicFee:
+		txType = raw[0]
+		item, err := rlpDecodeSingle(raw[1:])
+		if err != nil {
+			return nil, err
+		}
+		fields = item.list
+		expected := 11
+		if txType == ethTxTypeDynamicFee {
+			expected = 12
+		}
+		if !item.isList || len(fields) != expected {
+			return nil, fmt.Errorf("malformed type %d transaction", txType)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", raw[0])
+	}
+
+	info := RawTransactionInfo{}
+	var (
+		v        *big.Int
+		r, s     []byte
+		unsigned []byte
+	)
+	switch txType {
+	case 0:
+		v = fields[6].bigInt()
+		r, s = fields[7].data, fields[8].data
+		sigFields := rlpRawItems(fields[:6])
+		switch {
+		case v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0:
+			v.Sub(v, big.NewInt(27))
+		case v.Cmp(big.NewInt(35)) >= 0:
+			// EIP-155: v = chainID * 2 + 35 + recovery ID.
+			v.Sub(v, big.NewInt(35))
+			info.ChainID = new(big.Int).Rsh(v, 1)
+			v.And(v, big.NewInt(1))
+			sigFields = append(sigFields, rlpEncodeBytes(info.ChainID.Bytes()), rlpEncodeBytes(nil), rlpEncodeBytes(nil))
+		default:
+			return nil, fmt.Errorf("malformed signature recovery ID")
+		}
+		unsigned = rlpEncodeList(sigFields...)
+	default:
+		n := len(fields)
+		info.ChainID = fields[0].bigInt()
+		v = fields[n-3].bigInt()
+		r, s = fields[n-2].data, fields[n-1].data
+		unsigned = append([]byte{txType}, rlpEncodeList(rlpRawItems(fields[:n-3])...)...)
+	}
+
+	nonce := fields[0]
+	if txType != 0 {
+		nonce = fields[1]
+	}
+	if len(nonce.data) > 8 || nonce.isList {
+		return nil, fmt.Errorf("malformed nonce")
+	}
+	info.Nonce = nonce.bigInt().Uint64()
+
+	to := fields[3]
+	switch txType {
+	case ethTxTypeAccessList:
+		to = fields[4]
+	case ethTxTypeDynamicFee:
+		to = fields[5]
+	}
+	switch {
+	case to.isList || (len(to.data) != 0 && len(to.data) != 20):
+		return nil, fmt.Errorf("malformed recipient")
+	case len(to.data) != 0:
+		info.To = append([]byte{}, to.data...)
+	}
+
+	if v.Cmp(big.NewInt(1)) > 0 || len(r) > 32 || len(s) > 32 {
+		return nil, fmt.Errorf("malformed signature")
+	}
+	sig := make([]byte, 65)
+	sig[0] = 27 + byte(v.Uint64())
+	copy(sig[33-len(r):33], r)
+	copy(sig[65-len(s):], s)
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write(unsigned)
+	pk, _, err := btcec.RecoverCompact(btcec.S256(), sig, h.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %w", err)
+	}
+	h = sha3.NewLegacyKeccak256()
+	h.Write(pk.SerializeUncompressed()[1:])
+	info.Sender = h.Sum(nil)[12:]
+
+	h = sha3.NewLegacyKeccak256()
+	h.Write(raw)
+	info.Hash = h.Sum(nil)
+
+	return &info, nil
+}
+
+// Implements V1.
+func (a *v1) SendRawTransaction(ctx context.Context, rawEthTx []byte) (cbor.RawMessage, error) {
+	info, err := DecodeRawTransaction(rawEthTx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: malformed raw transaction: %w", err)
+	}
+
+	tx := &types.UnverifiedTransaction{
+		Body:       rawEthTx,
+		AuthProofs: []types.AuthProof{{Module: ethereumTxScheme}},
+	}
+	result, err := a.rtc.SubmitTx(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("evm: raw transaction from 0x%s with nonce %d failed: %w", hex.EncodeToString(info.Sender), info.Nonce, err)
+	}
+	return result, nil
+}
+
+// rlpItem is a decoded RLP item.
+type rlpItem struct {
+	// raw is the complete encoding of the item.
+	raw []byte
+	// data is the payload of a string item.
+	data []byte
+	// list are the elements of a list item.
+	list   []rlpItem
+	isList bool
+}
+
+func (it *rlpItem) bigInt() *big.Int {
+	return new(big.Int).SetBytes(it.data)
+}
+
+// rlpDecodeSingle decodes the given data as exactly one RLP item.
+func rlpDecodeSingle(data []byte) (*rlpItem, error) {
+	item, rest, err := rlpDecode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("rlp: trailing data")
+	}
+	return item, nil
+}
+
+// rlpDecode decodes the first RLP item from the given data and returns the remaining data.
+func rlpDecode(data []byte) (*rlpItem, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("rlp: unexpected end of data")
+	}
+
+	prefix := data[0]
+	var (
+		offset, size uint64
+		isList       bool
+	)
+	switch {
+	case prefix < 0x80:
+		offset, size = 0, 1
+	case prefix <= 0xb7:
+		offset, size = 1, uint64(prefix-0x80)
+	case prefix < 0xc0:
+		lenSize := uint64(prefix - 0xb7)
+		offset = 1 + lenSize
+		if uint64(len(data)) < offset {
+			return nil, nil, fmt.Errorf("rlp: unexpected end of data")
+		}
+		size = new(big.Int).SetBytes(data[1:offset]).Uint64()
+	case prefix <= 0xf7:
+		offset, size, isList = 1, uint64(prefix-0xc0), true
+	default:
+		lenSize := uint64(prefix - 0xf7)
+		offset, isList = 1+lenSize, true
+		if uint64(len(data)) < offset {
+			return nil, nil, fmt.Errorf("rlp: unexpected end of data")
+		}
+		size = new(big.Int).SetBytes(data[1:offset]).Uint64()
+	}
+	if size > uint64(len(data))-offset {
+		return nil, nil, fmt.Errorf("rlp: unexpected end of data")
+	}
+
+	end := offset + size
+	item := &rlpItem{
+		raw:    data[:end],
+		isList: isList,
+	}
+	if !isList {
+		item.data = data[offset:end]
+		return item, data[end:], nil
+	}
+
+	payload := data[offset:end]
+	for len(payload) > 0 {
+		elem, rest, err := rlpDecode(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		item.list = append(item.list, *elem)
+		payload = rest
+	}
+	return item, data[end:], nil
+}
+
+// rlpRawItems returns the complete encodings of the given items.
+func rlpRawItems(items []rlpItem) [][]byte {
+	raw := make([][]byte, 0, len(items))
+	for _, item := range items {
+		raw = append(raw, item.raw)
+	}
+	return raw
+}
+
+// rlpHeader encodes the header of an RLP item with a payload of the given size.
+func rlpHeader(shortOffset byte, size int) []byte {
+	if size <= 55 {
+		return []byte{shortOffset + byte(size)}
+	}
+	encSize := big.NewInt(int64(size)).Bytes()
+	return append([]byte{shortOffset + 55 + byte(len(encSize))}, encSize...)
+}
+
+// rlpEncodeBytes RLP-encodes the given byte string.
+func rlpEncodeBytes(data []byte) []byte {
+	if len(data) == 1 && data[0] < 0x80 {
+		return []byte{data[0]}
+	}
+	return append(rlpHeader(0x80, len(data)), data...)
+}
+
+// rlpEncodeList RLP-encodes a list of already encoded items.
+func rlpEncodeList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(rlpHeader(0xc0, len(payload)), payload...)
+}
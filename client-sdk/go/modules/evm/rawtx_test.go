@@ -0,0 +1,107 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func TestDecodeRawTransaction(t *testing.T) {
+	require := require.New(t)
+
+	// Example transaction from EIP-155.
+	raw, _ := hex.DecodeString("f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83")
+	info, err := DecodeRawTransaction(raw)
+	require.NoError(err, "DecodeRawTransaction")
+	require.EqualValues(1, info.ChainID.Int64())
+	require.EqualValues(9, info.Nonce)
+	require.Equal("9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", hex.EncodeToString(info.Sender))
+	require.Equal("3535353535353535353535353535353535353535", hex.EncodeToString(info.To))
+	require.Equal("33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788", hex.EncodeToString(info.Hash))
+
+	sk, _ := btcec.NewPrivateKey(btcec.S256())
+	sender := keccak256(sk.PubKey().SerializeUncompressed()[1:])[12:]
+	sign := func(unsigned []byte) (*big.Int, []byte, []byte) {
+		sig, serr := btcec.SignCompact(btcec.S256(), sk, keccak256(unsigned), false)
+		require.NoError(serr, "SignCompact")
+		return big.NewInt(int64(sig[0] - 27)), sig[1:33], sig[33:]
+	}
+	encUint := func(v uint64) []byte {
+		return rlpEncodeBytes(new(big.Int).SetUint64(v).Bytes())
+	}
+	to := rlpEncodeBytes(make([]byte, 20))
+
+	// Legacy transaction without replay protection.
+	fields := [][]byte{encUint(300), encUint(1), encUint(21_000), to, encUint(0), rlpEncodeBytes(nil)}
+	v, r, s := sign(rlpEncodeList(fields...))
+	raw = rlpEncodeList(append(fields, encUint(27+v.Uint64()), rlpEncodeBytes(r), rlpEncodeBytes(s))...)
+	info, err = DecodeRawTransaction(raw)
+	require.NoError(err, "DecodeRawTransaction")
+	require.Nil(info.ChainID)
+	require.EqualValues(300, info.Nonce)
+	require.Equal(sender, info.Sender)
+	require.Equal(make([]byte, 20), info.To)
+	require.Equal(keccak256(raw), info.Hash)
+
+	// EIP-1559 transaction.
+	fields = [][]byte{encUint(42), encUint(7), encUint(1), encUint(100), encUint(21_000), rlpEncodeBytes(nil), encUint(0), rlpEncodeBytes([]byte{1, 2, 3}), rlpEncodeList()}
+	v, r, s = sign(append([]byte{ethTxTypeDynamicFee}, rlpEncodeList(fields...)...))
+	raw = append([]byte{ethTxTypeDynamicFee}, rlpEncodeList(append(fields, encUint(v.Uint64()), rlpEncodeBytes(r), rlpEncodeBytes(s))...)...)
+	info, err = DecodeRawTransaction(raw)
+	require.NoError(err, "DecodeRawTransaction")
+	require.EqualValues(42, info.ChainID.Int64())
+	require.EqualValues(7, info.Nonce)
+	require.Equal(sender, info.Sender)
+	require.Nil(info.To, "contract creation should have no recipient")
+
+	for _, tc := range []struct {
+		raw string
+		msg string
+	}{
+		{"", "empty transaction"},
+		{"03c0", "unsupported transaction type"},
+		{"c0", "legacy transaction with missing fields"},
+		{"f86c09", "truncated transaction"},
+		{"02c0", "typed transaction with missing fields"},
+	} {
+		raw, _ = hex.DecodeString(tc.raw)
+		_, err = DecodeRawTransaction(raw)
+		require.Error(err, tc.msg)
+	}
+}
+
+func TestSendRawTransaction(t *testing.T) {
+	require := require.New(t)
+
+	raw, _ := hex.DecodeString("f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83")
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetSubmitHandler(func(tx *types.UnverifiedTransaction) (*types.CallResult, error) {
+		return nil, fmt.Errorf("nonce too low")
+	})
+	_, err := NewV1(mc).SendRawTransaction(context.Background(), raw)
+	require.Error(err, "SendRawTransaction should propagate submission errors")
+	require.Contains(err.Error(), "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f")
+	require.Contains(err.Error(), "nonce 9")
+	submitted := mc.SubmittedTransactions()
+	require.Len(submitted, 1)
+	require.Equal(raw, submitted[0].Body, "raw transaction should be submitted as is")
+	require.Equal([]types.AuthProof{{Module: "evm.ethereum.v0"}}, submitted[0].AuthProofs)
+
+	_, err = NewV1(mc).SendRawTransaction(context.Background(), []byte{0xc0})
+	require.Error(err, "SendRawTransaction should reject malformed transactions")
+}
@@ -0,0 +1,155 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+// ErrReceiptNotFound is the error returned by GetReceipt and GetReceiptInRound in case the
+// transaction is not found.
+var ErrReceiptNotFound = errors.New("evm: transaction receipt not found")
+
+const (
+	// ReceiptStatusFailed is the status of a failed transaction.
+	ReceiptStatusFailed = 0
+	// ReceiptStatusSuccessful is the status of a successful transaction.
+	ReceiptStatusSuccessful = 1
+)
+
+// Receipt is the receipt of an EVM transaction, similar to an Ethereum transaction receipt.
+//
+// Unlike Ethereum receipts, it does not include the amount of gas used as the runtime does not
+// report gas usage of individual transactions.
+type Receipt struct {
+	// Round is the round in which the transaction was executed.
+	Round uint64
+	// TransactionIndex is the index of the transaction within the round.
+	TransactionIndex uint32
+	// TransactionHash is the hash the transaction was looked up by.
+	TransactionHash []byte
+
+	// Status is either ReceiptStatusSuccessful or ReceiptStatusFailed.
+	Status uint64
+	// Failure is the reason for failure in case the transaction failed.
+	Failure *types.FailedCallResult
+	// ContractAddress is the address of the created contract in case the transaction is a
+	// successful contract creation and nil otherwise.
+	ContractAddress []byte
+	// Logs are the logs emitted by the transaction.
+	Logs []*Log
+}
+
+// Implements V1.
+func (a *v1) GetReceipt(ctx context.Context, txHash []byte) (*Receipt, error) {
+	blk, err := a.rtc.GetBlock(ctx, client.RoundLatest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest block: %w", err)
+	}
+	latest := blk.Header.Round
+
+	for round := latest; latest-round < MaxReceiptSearchRounds; round-- {
+		receipt, rerr := a.GetReceiptInRound(ctx, round, txHash)
+		if !errors.Is(rerr, ErrReceiptNotFound) {
+			return receipt, rerr
+		}
+		if round == 0 {
+			break
+		}
+	}
+	return nil, ErrReceiptNotFound
+}
+
+// Implements V1.
+func (a *v1) GetReceiptInRound(ctx context.Context, round uint64, txHash []byte) (*Receipt, error) {
+	txs, err := a.rtc.GetTransactionsWithResults(ctx, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transactions for round %d: %w", round, err)
+	}
+
+	for i, tx := range txs {
+		matches, isCreate, merr := receiptTxMatches(tx, txHash)
+		if merr != nil {
+			return nil, fmt.Errorf("transaction %d in round %d: %w", i, round, merr)
+		}
+		if !matches {
+			continue
+		}
+
+		receipt := Receipt{
+			Round:            round,
+			TransactionIndex: uint32(i),
+			TransactionHash:  txHash,
+			Status:           ReceiptStatusSuccessful,
+		}
+		switch {
+		case tx.Result.IsUnknown():
+			return nil, fmt.Errorf("transaction has an unknown result")
+		case !tx.Result.IsSuccess():
+			receipt.Status = ReceiptStatusFailed
+			receipt.Failure = tx.Result.Failed
+		case isCreate:
+			if err = cbor.Unmarshal(tx.Result.Ok, &receipt.ContractAddress); err != nil {
+				return nil, fmt.Errorf("malformed contract creation result: %w", err)
+			}
+		}
+
+		for _, rev := range tx.Events {
+			ev, derr := a.DecodeEvent(rev)
+			if derr != nil {
+				return nil, derr
+			}
+			if ev != nil {
+				receipt.Logs = append(receipt.Logs, &Log{Event: *ev.(*Event), Round: round})
+			}
+		}
+		return &receipt, nil
+	}
+	return nil, ErrReceiptNotFound
+}
+
+// receiptTxMatches checks whether the given transaction matches the given hash and whether it is
+// a contract creation. The hash can either be the SDK transaction hash or, for Ethereum-encoded
+// transactions, the Ethereum transaction hash. An error is returned in case a matching
+// transaction is not an EVM transaction.
+func receiptTxMatches(tx *client.TransactionWithResults, txHash []byte) (bool, bool, error) {
+	isEthereum := len(tx.Tx.AuthProofs) == 1 && tx.Tx.AuthProofs[0].Module == ethereumTxScheme
+	matches := bytes.Equal(tx.Hash[:], txHash)
+	if !matches && isEthereum {
+		h := sha3.NewLegacyKeccak256()
+		h.Write(tx.Tx.Body)
+		matches = bytes.Equal(h.Sum(nil), txHash)
+	}
+	if !matches {
+		return false, false, nil
+	}
+
+	if isEthereum {
+		info, err := DecodeRawTransaction(tx.Tx.Body)
+		if err != nil {
+			return false, false, err
+		}
+		return true, info.To == nil, nil
+	}
+
+	var decoded types.Transaction
+	if err := cbor.Unmarshal(tx.Tx.Body, &decoded); err != nil {
+		return false, false, err
+	}
+	switch decoded.Call.Method {
+	case methodCreate:
+		return true, true, nil
+	case methodCall:
+		return true, false, nil
+	default:
+		return false, false, fmt.Errorf("not an EVM transaction (method: %s)", decoded.Call.Method)
+	}
+}
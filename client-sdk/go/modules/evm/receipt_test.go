@@ -0,0 +1,109 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
+	"github.com/oasisprotocol/oasis-core/go/roothash/api/block"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
+)
+
+func TestGetReceipt(t *testing.T) {
+	require := require.New(t)
+
+	newTx := func(ut types.UnverifiedTransaction, result types.CallResult, events ...*types.Event) *client.TransactionWithResults {
+		return &client.TransactionWithResults{
+			Tx:     ut,
+			Result: result,
+			Events: events,
+		}
+	}
+
+	contract := []byte("contractcontractcont")
+	log := &Event{Address: contract, Topics: [][]byte{{0x01}}, Data: []byte{0x02}}
+	create := newTx(
+		types.UnverifiedTransaction{Body: cbor.Marshal(types.NewTransaction(nil, methodCreate, &Create{}))},
+		types.CallResult{Ok: cbor.Marshal(contract)},
+		&types.Event{Module: ModuleName, Code: 1, Value: cbor.Marshal(log)},
+		&types.Event{Module: "accounts", Code: 1, Value: cbor.Marshal(nil)},
+	)
+	rawEthTx, _ := hex.DecodeString("f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83")
+	ethCall := newTx(
+		types.UnverifiedTransaction{Body: rawEthTx, AuthProofs: []types.AuthProof{{Module: ethereumTxScheme}}},
+		types.CallResult{Failed: &types.FailedCallResult{Module: ModuleName, Code: 8}},
+	)
+	transfer := newTx(
+		types.UnverifiedTransaction{Body: cbor.Marshal(types.NewTransaction(nil, "accounts.Transfer", nil))},
+		types.CallResult{Ok: cbor.Marshal(nil)},
+	)
+	mc := clienttest.NewMockRuntimeClient()
+	mc.SetTransactions(10, []*client.TransactionWithResults{transfer, create, ethCall})
+	evm := NewV1(mc)
+	ctx := context.Background()
+
+	receipt, err := evm.GetReceiptInRound(ctx, 10, create.Hash[:])
+	require.NoError(err, "GetReceiptInRound")
+	require.EqualValues(10, receipt.Round)
+	require.EqualValues(1, receipt.TransactionIndex)
+	require.EqualValues(ReceiptStatusSuccessful, receipt.Status)
+	require.Nil(receipt.Failure)
+	require.Equal(contract, receipt.ContractAddress)
+	require.Equal([]*Log{{Event: *log, Round: 10}}, receipt.Logs, "only EVM events should be included as logs")
+
+	ethHash, _ := hex.DecodeString("33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788")
+	for _, h := range [][]byte{ethHash, ethCall.Hash[:]} {
+		receipt, err = evm.GetReceiptInRound(ctx, 10, h)
+		require.NoError(err, "GetReceiptInRound")
+		require.EqualValues(2, receipt.TransactionIndex)
+		require.EqualValues(ReceiptStatusFailed, receipt.Status)
+		require.EqualValues(8, receipt.Failure.Code)
+		require.Nil(receipt.ContractAddress)
+		require.Empty(receipt.Logs)
+	}
+
+	_, err = evm.GetReceiptInRound(ctx, 10, transfer.Hash[:])
+	require.Error(err, "GetReceiptInRound should fail for non-EVM transactions")
+	require.False(errors.Is(err, ErrReceiptNotFound))
+
+	unknown := hash.NewFromBytes([]byte("unknown"))
+	_, err = evm.GetReceiptInRound(ctx, 10, unknown[:])
+	require.True(errors.Is(err, ErrReceiptNotFound), "GetReceiptInRound should return ErrReceiptNotFound")
+	_, err = evm.GetReceiptInRound(ctx, 11, create.Hash[:])
+	require.True(errors.Is(err, ErrReceiptNotFound), "GetReceiptInRound should only look at the given round")
+
+	// Lookup by hash only scans the most recent rounds.
+	for _, tc := range []struct {
+		latest uint64
+		found  bool
+	}{
+		{10, true},
+		{10 + MaxReceiptSearchRounds - 1, true},
+		{10 + MaxReceiptSearchRounds, false},
+	} {
+		mc = clienttest.NewMockRuntimeClient()
+		mc.SetTransactions(10, []*client.TransactionWithResults{transfer, create, ethCall})
+		var blk block.Block
+		blk.Header.Round = tc.latest
+		mc.SetBlock(&blk)
+		evm = NewV1(mc)
+
+		for _, h := range [][]byte{create.Hash[:], ethHash} {
+			receipt, err = evm.GetReceipt(ctx, h)
+			if !tc.found {
+				require.True(errors.Is(err, ErrReceiptNotFound), "GetReceipt should not scan past the search limit (latest: %d)", tc.latest)
+				continue
+			}
+			require.NoError(err, "GetReceipt (latest: %d)", tc.latest)
+			require.EqualValues(10, receipt.Round)
+		}
+	}
+}
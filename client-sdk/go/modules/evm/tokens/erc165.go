@@ -0,0 +1,140 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm"
+)
+
+var (
+	// InterfaceIDERC165 is the ERC-165 interface identifier of ERC-165 itself.
+	InterfaceIDERC165 = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	// InterfaceIDERC20 is the ERC-165 interface identifier of ERC-20.
+	InterfaceIDERC20 = [4]byte{0x36, 0x37, 0x2b, 0x07}
+	// InterfaceIDERC721 is the ERC-165 interface identifier of ERC-721.
+	InterfaceIDERC721 = [4]byte{0x80, 0xac, 0x58, 0xcd}
+
+	interfaceIDInvalid = [4]byte{0xff, 0xff, 0xff, 0xff}
+)
+
+const (
+	// EVM module error codes indicating that the contract call failed or reverted.
+	evmErrExecutionFailed = 2
+	evmErrReverted        = 8
+)
+
+// Standard is a token contract standard.
+type Standard uint8
+
+const (
+	// StandardUnknown means that the contract does not implement a known token standard.
+	StandardUnknown Standard = iota
+	// StandardERC20 is the ERC-20 fungible token standard.
+	StandardERC20
+	// StandardERC721 is the ERC-721 non-fungible token standard.
+	StandardERC721
+)
+
+// String returns a string representation of the token standard.
+func (s Standard) String() string {
+	switch s {
+	case StandardERC20:
+		return "ERC-20"
+	case StandardERC721:
+		return "ERC-721"
+	default:
+		return "unknown"
+	}
+}
+
+// SupportsInterface checks whether the contract at the given address implements the interface
+// with the given ERC-165 identifier.
+//
+// As specified by ERC-165, the contract must itself support ERC-165 detection. Contracts which
+// fail or revert when queried are reported as not supporting the interface.
+func SupportsInterface(ctx context.Context, rc client.RuntimeClient, round uint64, address []byte, interfaceID [4]byte) (bool, error) {
+	c := newContract(rc, address)
+
+	supported, err := c.supportsInterface(ctx, round, InterfaceIDERC165)
+	if err != nil || !supported {
+		return false, err
+	}
+	supported, err = c.supportsInterface(ctx, round, interfaceIDInvalid)
+	if err != nil || supported {
+		return false, err
+	}
+	if interfaceID == InterfaceIDERC165 {
+		return true, nil
+	}
+	return c.supportsInterface(ctx, round, interfaceID)
+}
+
+// DetectStandard classifies the token contract at the given address.
+//
+// Contracts advertising ERC-721 or ERC-20 support via ERC-165 are classified accordingly. Since
+// most ERC-20 tokens predate ERC-165, contracts answering the ERC-20 decimals and totalSupply
+// queries are classified as ERC-20 tokens as well.
+func DetectStandard(ctx context.Context, rc client.RuntimeClient, round uint64, address []byte) (Standard, error) {
+	for _, s := range []struct {
+		interfaceID [4]byte
+		standard    Standard
+	}{
+		{InterfaceIDERC721, StandardERC721},
+		{InterfaceIDERC20, StandardERC20},
+	} {
+		supported, err := SupportsInterface(ctx, rc, round, address, s.interfaceID)
+		if err != nil {
+			return StandardUnknown, err
+		}
+		if supported {
+			return s.standard, nil
+		}
+	}
+
+	token := NewERC20(rc, address)
+	for _, probe := range []func() error{
+		func() error {
+			_, err := token.Decimals(ctx, round)
+			return err
+		},
+		func() error {
+			_, err := token.TotalSupply(ctx, round)
+			return err
+		},
+	} {
+		if err := probe(); err != nil {
+			if isContractFailure(err) {
+				return StandardUnknown, nil
+			}
+			return StandardUnknown, err
+		}
+	}
+	return StandardERC20, nil
+}
+
+// supportsInterface invokes supportsInterface(bytes4) on the contract.
+func (c *contract) supportsInterface(ctx context.Context, round uint64, interfaceID [4]byte) (bool, error) {
+	var supported bool
+	err := c.query(ctx, round, "supportsInterface(bytes4)", []interface{}{interfaceID[:]}, []string{"bool"}, &supported)
+	if err != nil {
+		if isContractFailure(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return supported, nil
+}
+
+// isContractFailure returns true in case the error indicates that the contract call failed
+// (e.g. reverted or returned malformed data) as opposed to the query itself failing.
+func isContractFailure(err error) bool {
+	if errors.Is(err, errMalformedResult) {
+		return true
+	}
+	module, code := coreErrors.Code(err)
+	return module == evm.ModuleName && (code == evmErrExecutionFailed || code == evmErrReverted)
+}
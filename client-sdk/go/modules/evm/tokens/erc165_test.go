@@ -0,0 +1,103 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	coreErrors "github.com/oasisprotocol/oasis-core/go/common/errors"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client/clienttest"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm/abi"
+)
+
+// mockContract describes the behavior of a contract served by a mock runtime client.
+type mockContract struct {
+	// interfaces are the interfaces supported via ERC-165 (nil if ERC-165 is not supported).
+	interfaces [][4]byte
+	// erc20 is true in case the contract answers ERC-20 queries.
+	erc20 bool
+	// failure is returned for all calls in case it is set.
+	failure error
+}
+
+// newClient returns a mock runtime client serving the contract.
+func (mc *mockContract) newClient() *clienttest.MockRuntimeClient {
+	rc := clienttest.NewMockRuntimeClient()
+	rc.SetQueryHandler("evm.SimulateCall", func(round uint64, args cbor.RawMessage) (interface{}, error) {
+		if mc.failure != nil {
+			return nil, mc.failure
+		}
+		var q evm.SimulateCallQuery
+		if err := cbor.Unmarshal(args, &q); err != nil {
+			return nil, err
+		}
+		return mc.call(q.Data)
+	})
+	return rc
+}
+
+func (mc *mockContract) call(data []byte) ([]byte, error) {
+	reverted := coreErrors.FromCode(evm.ModuleName, evmErrReverted, "reverted")
+
+	switch {
+	case bytes.Equal(data[:4], abi.MethodID("supportsInterface(bytes4)")):
+		if mc.interfaces == nil {
+			return nil, reverted
+		}
+		var supported bool
+		for _, id := range mc.interfaces {
+			if bytes.Equal(id[:], data[4:8]) {
+				supported = true
+			}
+		}
+		return abi.Pack([]string{"bool"}, supported)
+	case bytes.Equal(data, abi.MethodID("decimals()")), bytes.Equal(data, abi.MethodID("totalSupply()")):
+		if !mc.erc20 {
+			return nil, reverted
+		}
+		return abi.Pack([]string{"uint256"}, big.NewInt(18))
+	default:
+		return nil, reverted
+	}
+}
+
+func TestDetectStandard(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	address := make([]byte, 20)
+	invalidID := [4]byte{0xff, 0xff, 0xff, 0xff}
+
+	for _, tc := range []struct {
+		contract *mockContract
+		standard Standard
+	}{
+		{&mockContract{interfaces: [][4]byte{InterfaceIDERC165, InterfaceIDERC721}}, StandardERC721},
+		{&mockContract{interfaces: [][4]byte{InterfaceIDERC165, InterfaceIDERC20}}, StandardERC20},
+		{&mockContract{erc20: true}, StandardERC20},
+		{&mockContract{interfaces: [][4]byte{InterfaceIDERC165}, erc20: true}, StandardERC20},
+		{&mockContract{interfaces: [][4]byte{InterfaceIDERC165}}, StandardUnknown},
+		{&mockContract{}, StandardUnknown},
+		// Contracts claiming to support every interface do not implement ERC-165 correctly.
+		{&mockContract{interfaces: [][4]byte{InterfaceIDERC165, InterfaceIDERC721, invalidID}}, StandardUnknown},
+	} {
+		standard, err := DetectStandard(ctx, tc.contract.newClient(), client.RoundLatest, address)
+		require.NoError(err, "DetectStandard")
+		require.Equal(tc.standard, standard, "DetectStandard(%+v)", tc.contract)
+	}
+
+	supported, err := SupportsInterface(ctx, (&mockContract{interfaces: [][4]byte{InterfaceIDERC165}}).newClient(), client.RoundLatest, address, InterfaceIDERC165)
+	require.NoError(err, "SupportsInterface")
+	require.True(supported, "ERC-165 should be supported")
+
+	_, err = DetectStandard(ctx, (&mockContract{failure: fmt.Errorf("network unreachable")}).newClient(), client.RoundLatest, address)
+	require.Error(err, "DetectStandard should propagate query failures")
+}
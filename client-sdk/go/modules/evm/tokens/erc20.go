@@ -0,0 +1,164 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm/abi"
+)
+
+// ERC20 is a client for an ERC-20 token contract.
+type ERC20 interface {
+	// Address returns the address of the token contract.
+	Address() []byte
+
+	// Name queries the name of the token.
+	Name(ctx context.Context, round uint64) (string, error)
+
+	// Symbol queries the symbol of the token.
+	Symbol(ctx context.Context, round uint64) (string, error)
+
+	// Decimals queries the number of decimals the token uses.
+	Decimals(ctx context.Context, round uint64) (uint8, error)
+
+	// TotalSupply queries the total token supply.
+	TotalSupply(ctx context.Context, round uint64) (*big.Int, error)
+
+	// BalanceOf queries the token balance of the given account.
+	BalanceOf(ctx context.Context, round uint64, owner []byte) (*big.Int, error)
+
+	// Transfer generates an EVM CALL transaction invoking transfer(to, amount).
+	Transfer(to []byte, amount *big.Int) (*client.TransactionBuilder, error)
+}
+
+type erc20 struct {
+	contract
+}
+
+// Implements ERC20.
+func (t *erc20) Name(ctx context.Context, round uint64) (string, error) {
+	var name string
+	if err := t.query(ctx, round, "name()", nil, []string{"string"}, &name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// Implements ERC20.
+func (t *erc20) Symbol(ctx context.Context, round uint64) (string, error) {
+	var symbol string
+	if err := t.query(ctx, round, "symbol()", nil, []string{"string"}, &symbol); err != nil {
+		return "", err
+	}
+	return symbol, nil
+}
+
+// Implements ERC20.
+func (t *erc20) Decimals(ctx context.Context, round uint64) (uint8, error) {
+	var decimals *big.Int
+	if err := t.query(ctx, round, "decimals()", nil, []string{"uint8"}, &decimals); err != nil {
+		return 0, err
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
+// Implements ERC20.
+func (t *erc20) TotalSupply(ctx context.Context, round uint64) (*big.Int, error) {
+	var supply *big.Int
+	if err := t.query(ctx, round, "totalSupply()", nil, []string{"uint256"}, &supply); err != nil {
+		return nil, err
+	}
+	return supply, nil
+}
+
+// Implements ERC20.
+func (t *erc20) BalanceOf(ctx context.Context, round uint64, owner []byte) (*big.Int, error) {
+	var balance *big.Int
+	if err := t.query(ctx, round, "balanceOf(address)", []interface{}{owner}, []string{"uint256"}, &balance); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// Implements ERC20.
+func (t *erc20) Transfer(to []byte, amount *big.Int) (*client.TransactionBuilder, error) {
+	return t.call(nil, "transfer(address,uint256)", to, amount)
+}
+
+// NewERC20 creates a new client for the ERC-20 token contract at the given address.
+func NewERC20(rc client.RuntimeClient, address []byte) ERC20 {
+	return &erc20{contract: newContract(rc, address)}
+}
+
+// contract contains the common functionality of EVM token contract clients.
+type contract struct {
+	evm     evm.V1
+	address []byte
+}
+
+// Address returns the address of the contract.
+func (c *contract) Address() []byte {
+	return c.address
+}
+
+// query simulates a call of the given method and decodes the results into the given outputs.
+func (c *contract) query(ctx context.Context, round uint64, method string, args []interface{}, outTypes []string, out ...interface{}) error {
+	data, err := abi.PackMethod(method, args...)
+	if err != nil {
+		return err
+	}
+
+	var caller [20]byte
+	raw, err := c.evm.SimulateCall(ctx, round, make([]byte, 32), simulateGasLimit, caller[:], c.address, make([]byte, 32), data)
+	if err != nil {
+		return fmt.Errorf("tokens: %s failed: %w", method, err)
+	}
+
+	values, err := abi.Unpack(outTypes, raw)
+	if err != nil {
+		return fmt.Errorf("tokens: %s: %w: %s", method, errMalformedResult, err)
+	}
+	for i, v := range values {
+		switch o := out[i].(type) {
+		case *string:
+			*o = v.(string)
+		case **big.Int:
+			*o = v.(*big.Int)
+		case *bool:
+			*o = v.(bool)
+		case *[20]byte:
+			*o = v.([20]byte)
+		default:
+			return fmt.Errorf("tokens: unsupported output type %T", out[i])
+		}
+	}
+	return nil
+}
+
+// call generates an EVM CALL transaction invoking the given method.
+//
+// A nil value means that no native tokens are transferred.
+func (c *contract) call(value *big.Int, method string, args ...interface{}) (*client.TransactionBuilder, error) {
+	data, err := abi.PackMethod(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	encValue, err := abi.Pack([]string{"uint256"}, value)
+	if err != nil {
+		return nil, fmt.Errorf("tokens: malformed value: %w", err)
+	}
+	return c.evm.Call(c.address, encValue, data), nil
+}
+
+func newContract(rc client.RuntimeClient, address []byte) contract {
+	return contract{
+		evm:     evm.NewV1(rc),
+		address: address,
+	}
+}
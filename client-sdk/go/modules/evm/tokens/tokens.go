@@ -0,0 +1,10 @@
+// Package tokens implements clients for common EVM token contract standards.
+package tokens
+
+import "errors"
+
+// errMalformedResult is the error returned when a contract call returns a malformed result.
+var errMalformedResult = errors.New("malformed result")
+
+// simulateGasLimit is the gas limit used when simulating read-only contract calls.
+const simulateGasLimit = 100_000
@@ -0,0 +1,44 @@
+package tokens
+
+import (
+	"math/big"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+)
+
+// WrappedNative is a client for a WETH-style contract wrapping the native token into an ERC-20
+// token.
+type WrappedNative interface {
+	ERC20
+
+	// Deposit generates an EVM CALL transaction invoking deposit() which wraps the given amount
+	// of native tokens.
+	Deposit(amount *big.Int) (*client.TransactionBuilder, error)
+
+	// Withdraw generates an EVM CALL transaction invoking withdraw(amount) which unwraps the given
+	// amount of wrapped tokens back into native tokens.
+	Withdraw(amount *big.Int) (*client.TransactionBuilder, error)
+}
+
+type wrappedNative struct {
+	erc20
+}
+
+// Implements WrappedNative.
+func (t *wrappedNative) Deposit(amount *big.Int) (*client.TransactionBuilder, error) {
+	return t.call(amount, "deposit()")
+}
+
+// Implements WrappedNative.
+func (t *wrappedNative) Withdraw(amount *big.Int) (*client.TransactionBuilder, error) {
+	return t.call(nil, "withdraw(uint256)", amount)
+}
+
+// NewWrappedNative creates a new client for the wrapped native token contract at the given
+// address.
+//
+// There is no canonical wrapper contract, so the address of the wrapper deployed on the given
+// runtime must be supplied by the caller.
+func NewWrappedNative(rc client.RuntimeClient, address []byte) WrappedNative {
+	return &wrappedNative{erc20: erc20{contract: newContract(rc, address)}}
+}
@@ -0,0 +1,44 @@
+package tokens
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/client"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm"
+)
+
+func decodeCall(t *testing.T, tb *client.TransactionBuilder) *evm.Call {
+	var body evm.Call
+	err := cbor.Unmarshal(tb.GetTransaction().Call.Body, &body)
+	require.NoError(t, err, "malformed call body")
+	return &body
+}
+
+func TestWrappedNative(t *testing.T) {
+	require := require.New(t)
+
+	address := make([]byte, 20)
+	address[19] = 0x42
+	token := NewWrappedNative(nil, address)
+	require.Equal(address, token.Address())
+
+	tb, err := token.Deposit(big.NewInt(1000))
+	require.NoError(err, "Deposit")
+	call := decodeCall(t, tb)
+	require.Equal(address, call.Address)
+	require.Equal("d0e30db0", hex.EncodeToString(call.Data), "deposit() should have no arguments")
+	require.EqualValues(1000, new(big.Int).SetBytes(call.Value).Int64(), "deposit should transfer native tokens")
+
+	tb, err = token.Withdraw(big.NewInt(1000))
+	require.NoError(err, "Withdraw")
+	call = decodeCall(t, tb)
+	require.Equal("2e1a7d4d", hex.EncodeToString(call.Data[:4]), "withdraw(uint256) selector")
+	require.EqualValues(1000, new(big.Int).SetBytes(call.Data[4:]).Int64())
+	require.Zero(new(big.Int).SetBytes(call.Value).Sign(), "withdraw should not transfer native tokens")
+}
@@ -1,5 +1,7 @@
 package evm
 
+import "fmt"
+
 // The types in this file must match the types from the evm module types
 // in runtime-sdk/modules/evm/src/types.rs.
 
@@ -9,6 +11,11 @@ type Create struct {
 	InitCode []byte `json:"init_code"`
 }
 
+// Validate implements client.BodyValidator.
+func (c *Create) Validate() error {
+	return validateValue(c.Value)
+}
+
 // Call is an EVM CALL transaction.
 type Call struct {
 	Address []byte `json:"address"`
@@ -16,6 +23,22 @@ type Call struct {
 	Data    []byte `json:"data"`
 }
 
+// Validate implements client.BodyValidator.
+func (c *Call) Validate() error {
+	if len(c.Address) != 20 {
+		return fmt.Errorf("malformed address (expected 20 bytes, got %d)", len(c.Address))
+	}
+	return validateValue(c.Value)
+}
+
+// validateValue checks that the given value fits into a 256-bit unsigned integer.
+func validateValue(value []byte) error {
+	if len(value) > 32 {
+		return fmt.Errorf("malformed value (expected at most 32 bytes, got %d)", len(value))
+	}
+	return nil
+}
+
 // StorageQuery queries the EVM storage.
 type StorageQuery struct {
 	Address []byte `json:"address"`
@@ -51,3 +74,11 @@ type Event struct {
 	Topics  [][]byte `json:"topics"`
 	Data    []byte   `json:"data"`
 }
+
+// Log is an EVM log together with the round in which it was emitted.
+type Log struct {
+	Event
+
+	// Round is the round in which the log was emitted.
+	Round uint64
+}
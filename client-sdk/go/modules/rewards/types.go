@@ -6,6 +6,12 @@ import (
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 )
 
+// ModuleName is the rewards module name.
+const ModuleName = "rewards"
+
+// AddressRewardPool is the address of the reward pool account.
+var AddressRewardPool = types.NewAddressForModule(ModuleName, []byte("reward-pool"))
+
 // RewardStep is one of the time periods in the reward schedule.
 type RewardStep struct {
 	Until  beacon.EpochTime `json:"until"`
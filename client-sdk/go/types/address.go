@@ -2,9 +2,11 @@ package types
 
 import (
 	"encoding"
+	"fmt"
 
 	"golang.org/x/crypto/sha3"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/crypto/address"
 	"github.com/oasisprotocol/oasis-core/go/common/encoding/bech32"
@@ -116,6 +118,54 @@ func (a Address) String() string {
 	return bech32Addr
 }
 
+// Bech32 returns the Bech32 encoding of the address using the given human readable part instead
+// of the default AddressBech32HRP.
+func (a Address) Bech32(hrp string) (string, error) {
+	if err := validateBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	return bech32.Encode(hrp, a[:])
+}
+
+// ParseAddressBech32 decodes a Bech32-encoded address with the given human readable part instead
+// of the default AddressBech32HRP.
+func ParseAddressBech32(hrp string, text string) (Address, error) {
+	if err := validateBech32HRP(hrp); err != nil {
+		return Address{}, err
+	}
+	decodedHRP, data, err := bech32.Decode(text)
+	if err != nil {
+		return Address{}, fmt.Errorf("address: %w", err)
+	}
+	if decodedHRP != hrp {
+		return Address{}, fmt.Errorf("address: incorrect bech32 human readable part: %s (expected: %s)", decodedHRP, hrp)
+	}
+	var a Address
+	if err = a.UnmarshalBinary(data); err != nil {
+		return Address{}, err
+	}
+	return a, nil
+}
+
+// maxBech32HRPSize is the maximum size of a human readable part such that an encoded address
+// does not exceed the 90 character limit of Bech32.
+const maxBech32HRPSize = 90 - 1 - 6 - (address.Size*8+4)/5
+
+// validateBech32HRP checks that the given human readable part is valid for encoding addresses.
+func validateBech32HRP(hrp string) error {
+	if len(hrp) == 0 || len(hrp) > maxBech32HRPSize {
+		return fmt.Errorf("address: invalid bech32 human readable part length: %d", len(hrp))
+	}
+	for _, c := range hrp {
+		// Only lowercase printable US-ASCII characters are allowed as mixed case is invalid and
+		// the encoding is lowercase.
+		if c < 33 || c > 126 || (c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("address: invalid bech32 human readable part: %q", hrp)
+		}
+	}
+	return nil
+}
+
 // NewAddress creates a new address from the given signature address specification.
 func NewAddress(spec SignatureAddressSpec) (a Address) {
 	var (
@@ -148,7 +198,25 @@ func NewAddressRaw(ctx address.Context, data []byte) Address {
 	return (Address)(address.NewAddress(ctx, data))
 }
 
+// NewAddressFromEth creates a new address from the given Ethereum address.
+//
+// This is the address under which the runtime tracks the account (e.g. its balances and nonce)
+// of the given Ethereum address.
+func NewAddressFromEth(ethAddress []byte) Address {
+	return NewAddressRaw(AddressV0Secp256k1EthContext, ethAddress)
+}
+
+// NewAddressForRuntime creates a new address of the runtime account for the given runtime.
+//
+// This is the consensus layer account holding tokens deposited into the runtime.
+func NewAddressForRuntime(id common.Namespace) Address {
+	return (Address)(staking.NewRuntimeAddress(id))
+}
+
 // NewAddressForModule creates a new address for a specific module and raw kind.
+//
+// This mirrors the runtime's derivation of addresses for accounts controlled by modules (e.g. the
+// reward pool or contract instances).
 func NewAddressForModule(module string, kind []byte) Address {
 	moduleBytes := []byte(module)
 	sepBytes := []byte(".")
@@ -3,10 +3,13 @@ package types
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasisprotocol/oasis-core/go/common"
+
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/secp256k1"
 )
@@ -61,6 +64,16 @@ func TestAddressModule(t *testing.T) {
 	require.EqualValues("oasis1qq398yyk4wt2zxhtt8c66raynelgt6ngh5yq87xg", addr.String())
 }
 
+func TestAddressRuntime(t *testing.T) {
+	require := require.New(t)
+
+	var runtimeID common.Namespace
+	err := runtimeID.UnmarshalHex("80000000000000002aff7f6dfb62720cfd735f2b037b81572fad1b7937d826b3")
+	require.NoError(err, "UnmarshalHex")
+	addr := NewAddressForRuntime(runtimeID)
+	require.EqualValues("oasis1qpllh99nhwzrd56px4txvl26atzgg4f3a58jzzad", addr.String())
+}
+
 func TestAddressRaw(t *testing.T) {
 	require := require.New(t)
 
@@ -68,3 +81,48 @@ func TestAddressRaw(t *testing.T) {
 	addr := NewAddressRaw(AddressV0Secp256k1EthContext, ethAddress)
 	require.EqualValues("oasis1qrk58a6j2qn065m6p06jgjyt032f7qucy5wqeqpt", addr.String())
 }
+
+func TestAddressFromEth(t *testing.T) {
+	require := require.New(t)
+
+	ethAddress, _ := hex.DecodeString("dce075e1c39b1ae0b75d554558b6451a226ffe00")
+	addr := NewAddressFromEth(ethAddress)
+	require.EqualValues("oasis1qrk58a6j2qn065m6p06jgjyt032f7qucy5wqeqpt", addr.String())
+}
+
+func TestAddressBech32(t *testing.T) {
+	require := require.New(t)
+
+	pk := ed25519.NewPublicKey("utrdHlX///////////////////////////////////8=")
+	addr := NewAddress(NewSignatureAddressSpecEd25519(pk))
+
+	enc, err := addr.Bech32(AddressBech32HRP.String())
+	require.NoError(err, "Bech32")
+	require.Equal(addr.String(), enc, "encoding with the default HRP should match String")
+
+	enc, err = addr.Bech32("test")
+	require.NoError(err, "Bech32")
+	require.True(strings.HasPrefix(enc, "test1"), "encoding should use the custom HRP")
+	dec, err := ParseAddressBech32("test", enc)
+	require.NoError(err, "ParseAddressBech32")
+	require.True(addr.Equal(dec), "address should round-trip")
+
+	_, err = ParseAddressBech32("oasis", enc)
+	require.Error(err, "ParseAddressBech32 should fail with a different HRP")
+	_, err = ParseAddressBech32("test", enc[:len(enc)-1]+"q")
+	require.Error(err, "ParseAddressBech32 should fail with an invalid checksum")
+	short, _ := addr.Bech32("test")
+	_, err = ParseAddressBech32("test", short[:10])
+	require.Error(err, "ParseAddressBech32 should fail with a truncated address")
+
+	for _, hrp := range []string{"", "Test", "te st", "tést", strings.Repeat("x", maxBech32HRPSize+1)} {
+		_, err = addr.Bech32(hrp)
+		require.Error(err, "Bech32 should fail with invalid HRP %q", hrp)
+		_, err = ParseAddressBech32(hrp, enc)
+		require.Error(err, "ParseAddressBech32 should fail with invalid HRP %q", hrp)
+	}
+
+	enc, err = addr.Bech32(strings.Repeat("x", maxBech32HRPSize))
+	require.NoError(err, "Bech32 with the longest HRP")
+	require.Len(enc, 90)
+}
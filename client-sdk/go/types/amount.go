@@ -0,0 +1,153 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
+)
+
+// ParseDecimalAmount parses a decimal token amount (e.g. "1.5") into base units of a denomination
+// using the given number of decimals (see accounts.DenominationInfo).
+//
+// Trailing zeros in the fractional part are allowed even beyond the number of decimals, but
+// amounts that cannot be represented in base units, negative amounts and exponents are rejected.
+func ParseDecimalAmount(amount string, decimals uint8) (*quantity.Quantity, error) {
+	parts := strings.SplitN(amount, ".", 2)
+	integer := parts[0]
+	var fraction string
+	if len(parts) == 2 {
+		fraction = strings.TrimRight(parts[1], "0")
+		if len(parts[1]) == 0 {
+			return nil, fmt.Errorf("malformed amount '%s': missing fractional part", amount)
+		}
+	}
+	if !isDecimalDigits(integer) || (len(parts) == 2 && !isDecimalDigits(parts[1])) {
+		return nil, fmt.Errorf("malformed amount '%s'", amount)
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("malformed amount '%s': too many decimals (maximum: %d)", amount, decimals)
+	}
+
+	var v big.Int
+	v.SetString(integer+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10)
+	q := quantity.NewQuantity()
+	if err := q.FromBigInt(&v); err != nil {
+		return nil, fmt.Errorf("malformed amount '%s': %w", amount, err)
+	}
+	return q, nil
+}
+
+// FormatDecimalAmount formats an amount in base units of a denomination as a decimal token amount
+// using the given number of decimals. Trailing zeros of the fractional part are omitted.
+func FormatDecimalAmount(amount quantity.Quantity, decimals uint8) string {
+	digits := amount.String()
+	if decimals == 0 {
+		return digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	split := len(digits) - int(decimals)
+	integer, fraction := digits[:split], strings.TrimRight(digits[split:], "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
+
+// ParseBaseUnits parses a token amount with an optional denomination (e.g. "1.5 TEST" or "1.5"
+// for the native denomination) using the given number of decimals for the amount.
+func ParseBaseUnits(s string, decimals uint8) (*BaseUnits, error) {
+	amount, denomination, err := splitTokenAmount(s)
+	if err != nil {
+		return nil, err
+	}
+	return parseBaseUnits(amount, denomination, decimals)
+}
+
+// ParseBaseUnitsList parses a comma-separated list of token amounts with optional denominations
+// (e.g. "10 TEST, 5 OTHER") using the number of decimals of each denomination as given in the
+// passed map. An empty string results in an empty list.
+//
+// An error is returned in case a denomination is not present in the map or in case it appears
+// more than once.
+func ParseBaseUnitsList(s string, decimals map[Denomination]uint8) ([]BaseUnits, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var list []BaseUnits
+	seen := make(map[Denomination]bool)
+	for _, item := range strings.Split(s, ",") {
+		amount, denomination, err := splitTokenAmount(item)
+		if err != nil {
+			return nil, err
+		}
+		d, ok := decimals[denomination]
+		if !ok {
+			return nil, fmt.Errorf("unknown denomination '%s'", denomination)
+		}
+		if seen[denomination] {
+			return nil, fmt.Errorf("duplicate denomination '%s'", denomination)
+		}
+		seen[denomination] = true
+
+		bu, err := parseBaseUnits(amount, denomination, d)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *bu)
+	}
+	return list, nil
+}
+
+// splitTokenAmount splits a token amount with an optional denomination into the amount and the
+// denomination.
+func splitTokenAmount(s string) (string, Denomination, error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		return fields[0], NativeDenomination, nil
+	case 2:
+		denomination := Denomination(fields[1])
+		if len(denomination) > MaxDenominationSize {
+			return "", "", fmt.Errorf("malformed denomination '%s'", fields[1])
+		}
+		return fields[0], denomination, nil
+	default:
+		return "", "", fmt.Errorf("malformed token amount '%s' (expected: <amount> [<denomination>])", s)
+	}
+}
+
+func parseBaseUnits(amount string, denomination Denomination, decimals uint8) (*BaseUnits, error) {
+	q, err := ParseDecimalAmount(amount, decimals)
+	if err != nil {
+		return nil, err
+	}
+	bu := NewBaseUnits(*q, denomination)
+	return &bu, nil
+}
+
+// FormatDecimal returns a string representation of this token amount using the given number of
+// decimals, as accepted by ParseBaseUnits.
+func (bu BaseUnits) FormatDecimal(decimals uint8) string {
+	amount := FormatDecimalAmount(bu.Amount, decimals)
+	if bu.Denomination.IsNative() {
+		return amount
+	}
+	return amount + " " + string(bu.Denomination)
+}
+
+func isDecimalDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimalAmount(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		amount   string
+		decimals uint8
+		expected string
+		valid    bool
+	}{
+		{"1", 0, "1", true},
+		{"1", 9, "1000000000", true},
+		{"1.5", 9, "1500000000", true},
+		{"0.000000001", 9, "1", true},
+		{"00.1", 2, "10", true},
+		{"1.50", 1, "15", true},
+		{"1.500000", 1, "15", true},
+		{"10.000", 0, "10", true},
+		{"123456789012345678901234567890", 18, "123456789012345678901234567890000000000000000000", true},
+		{"1.05", 1, "", false},
+		{"0.0000000001", 9, "", false},
+		{"-1", 9, "", false},
+		{"-0.5", 9, "", false},
+		{"+1", 9, "", false},
+		{"1e9", 9, "", false},
+		{"", 9, "", false},
+		{".", 9, "", false},
+		{".5", 9, "", false},
+		{"1.", 9, "", false},
+		{"1.2.3", 9, "", false},
+		{"1,5", 9, "", false},
+		{" 1", 9, "", false},
+	} {
+		q, err := ParseDecimalAmount(tc.amount, tc.decimals)
+		if !tc.valid {
+			require.Error(err, "ParseDecimalAmount(%q, %d) should fail", tc.amount, tc.decimals)
+			continue
+		}
+		require.NoError(err, "ParseDecimalAmount(%q, %d)", tc.amount, tc.decimals)
+		require.Equal(tc.expected, q.String(), "ParseDecimalAmount(%q, %d)", tc.amount, tc.decimals)
+	}
+}
+
+func TestFormatDecimalAmount(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		amount   string
+		decimals uint8
+		expected string
+	}{
+		{"0", 0, "0"},
+		{"0", 9, "0"},
+		{"1", 9, "0.000000001"},
+		{"1500000000", 9, "1.5"},
+		{"1000000000", 9, "1"},
+		{"123", 2, "1.23"},
+		{"123", 3, "0.123"},
+		{"123", 0, "123"},
+	} {
+		q, err := ParseDecimalAmount(tc.amount, 0)
+		require.NoError(err, "ParseDecimalAmount")
+		formatted := FormatDecimalAmount(*q, tc.decimals)
+		require.Equal(tc.expected, formatted, "FormatDecimalAmount(%s, %d)", tc.amount, tc.decimals)
+
+		// Formatting should round-trip.
+		parsed, err := ParseDecimalAmount(formatted, tc.decimals)
+		require.NoError(err, "ParseDecimalAmount")
+		require.Equal(q.String(), parsed.String(), "formatted amount should round-trip")
+	}
+}
+
+func TestParseBaseUnits(t *testing.T) {
+	require := require.New(t)
+
+	bu, err := ParseBaseUnits("1.5 TEST", 6)
+	require.NoError(err, "ParseBaseUnits")
+	require.Equal("1500000", bu.Amount.String())
+	require.Equal(Denomination("TEST"), bu.Denomination)
+	require.Equal("1.5 TEST", bu.FormatDecimal(6))
+
+	bu, err = ParseBaseUnits("2", 9)
+	require.NoError(err, "ParseBaseUnits")
+	require.True(bu.Denomination.IsNative(), "denomination should default to native")
+	require.Equal("2", bu.FormatDecimal(9))
+
+	for _, s := range []string{"", "1 TEST extra", "-1 TEST", "1.5 " + string(make([]byte, MaxDenominationSize+1))} {
+		_, err = ParseBaseUnits(s, 6)
+		require.Error(err, "ParseBaseUnits(%q) should fail", s)
+	}
+}
+
+func TestParseBaseUnitsList(t *testing.T) {
+	require := require.New(t)
+
+	decimals := map[Denomination]uint8{
+		NativeDenomination: 9,
+		"TEST":             6,
+		"OTHER":            0,
+	}
+
+	list, err := ParseBaseUnitsList("10 TEST, 5 OTHER,1.5", decimals)
+	require.NoError(err, "ParseBaseUnitsList")
+	require.Len(list, 3)
+	require.Equal("10000000 TEST", list[0].String())
+	require.Equal("5 OTHER", list[1].String())
+	require.Equal("1500000000 <native>", list[2].String())
+
+	list, err = ParseBaseUnitsList(" ", decimals)
+	require.NoError(err, "ParseBaseUnitsList")
+	require.Empty(list)
+
+	for _, s := range []string{"1 UNKNOWN", "1 TEST,2 TEST", "1.5 OTHER", "1 TEST,", "1 TEST 2"} {
+		_, err = ParseBaseUnitsList(s, decimals)
+		require.Error(err, "ParseBaseUnitsList(%q) should fail", s)
+	}
+}
@@ -2,9 +2,11 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
@@ -27,6 +29,14 @@ type AuthProof struct {
 	Module string `json:"module,omitempty"`
 }
 
+// Signature is a detached transaction signature produced by TransactionSigner.ProducePartial.
+type Signature struct {
+	// PublicKey is the public key of the signer.
+	PublicKey PublicKey `json:"pk"`
+	// Signature is the signature over the transaction.
+	Signature []byte `json:"signature"`
+}
+
 // UnverifiedTransaction is an unverified transaction.
 type UnverifiedTransaction struct {
 	_ struct{} `cbor:",toarray"`
@@ -35,7 +45,22 @@ type UnverifiedTransaction struct {
 	AuthProofs []AuthProof
 }
 
+// Hash returns the hash of the transaction as submitted to the runtime.
+//
+// The hash is computed over the CBOR serialization of the signed transaction, the same way nodes
+// compute it, so it does not require network access and can be recorded before the transaction
+// is submitted (e.g., when carrying a transaction signed offline to an online machine). It
+// matches the hash reported by nodes (see client.TransactionWithResults.Hash) and can be used to
+// wait for the transaction's results via client.RuntimeClient.WaitForTxResults.
+func (ut *UnverifiedTransaction) Hash() hash.Hash {
+	return hash.NewFromBytes(cbor.Marshal(ut))
+}
+
 // Verify verifies and deserializes the unverified transaction.
+//
+// All signatures are checked against the signers declared in the transaction's AuthInfo under
+// the given chain context, including the thresholds of multisig signers, so transactions
+// received from untrusted sources can be inspected safely before being submitted.
 func (ut *UnverifiedTransaction) Verify(ctx signature.Context) (*Transaction, error) {
 	if len(ut.AuthProofs) == 1 && ut.AuthProofs[0].Module != "" {
 		return nil, fmt.Errorf("module-controlled decoding (scheme %q) not supported", ut.AuthProofs[0].Module)
@@ -149,6 +174,92 @@ func (ts *TransactionSigner) UnverifiedTransaction() *UnverifiedTransaction {
 	return &ts.ut
 }
 
+// ProducePartial signs the transaction and returns the signature without attaching it.
+//
+// The signature can later be attached (possibly to a different TransactionSigner prepared from
+// the same transaction) using AttachSignature. This makes it possible to collect signatures from
+// multiple parties or devices. The signer must be specified in the AuthInfo.
+func (ts *TransactionSigner) ProducePartial(ctx signature.Context, signer signature.Signer) (*Signature, error) {
+	pk := signer.Public()
+	if !ts.hasSigner(pk) {
+		return nil, fmt.Errorf("transaction: signer not found in AuthInfo")
+	}
+
+	sig, err := signer.ContextSign(ctx.New(SignatureContextBase), ts.ut.Body)
+	if err != nil {
+		return nil, fmt.Errorf("transaction: failed to sign transaction: %w", err)
+	}
+	return &Signature{
+		PublicKey: PublicKey{PublicKey: pk},
+		Signature: sig,
+	}, nil
+}
+
+// AttachSignature attaches a signature produced by ProducePartial to all signer infos with the
+// given address specification.
+//
+// In case of a multisig address specification the signature is attached for the multisig signer
+// with the signature's public key. The signature itself is not verified.
+func (ts *TransactionSigner) AttachSignature(spec AddressSpec, sig *Signature) error {
+	if sig == nil || sig.PublicKey.PublicKey == nil {
+		return fmt.Errorf("transaction: malformed signature")
+	}
+
+	encSpec := cbor.Marshal(spec)
+	any := false
+	for i, si := range ts.tx.AuthInfo.SignerInfo {
+		if !bytes.Equal(cbor.Marshal(si.AddressSpec), encSpec) {
+			continue
+		}
+
+		switch {
+		case si.AddressSpec.Signature != nil:
+			if !si.AddressSpec.Signature.PublicKey().Equal(sig.PublicKey.PublicKey) {
+				return fmt.Errorf("signer info %d: signature public key does not match", i)
+			}
+
+			any = true
+			ts.allocateProofs()
+			ts.ut.AuthProofs[i].Signature = sig.Signature
+		case si.AddressSpec.Multisig != nil:
+			for j, mss := range si.AddressSpec.Multisig.Signers {
+				if !mss.PublicKey.Equal(sig.PublicKey.PublicKey) {
+					continue
+				}
+
+				any = true
+				ts.allocateProofs()
+				ts.ut.AuthProofs[i].Multisig[j] = sig.Signature
+			}
+		default:
+			return fmt.Errorf("signer info %d: malformed AddressSpec", i)
+		}
+	}
+	if !any {
+		return fmt.Errorf("transaction: signer not found in AuthInfo")
+	}
+	return nil
+}
+
+// hasSigner checks whether the given public key is a signer specified in the AuthInfo.
+func (ts *TransactionSigner) hasSigner(pk signature.PublicKey) bool {
+	for _, si := range ts.tx.AuthInfo.SignerInfo {
+		switch {
+		case si.AddressSpec.Signature != nil:
+			if si.AddressSpec.Signature.PublicKey().Equal(pk) {
+				return true
+			}
+		case si.AddressSpec.Multisig != nil:
+			for _, mss := range si.AddressSpec.Multisig.Signers {
+				if mss.PublicKey.Equal(pk) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // Transaction is a runtime transaction.
 type Transaction struct {
 	cbor.Versioned
@@ -188,6 +299,24 @@ func (t *Transaction) AppendAuthMultisig(config *MultisigConfig, nonce uint64) {
 	t.AppendSignerInfo(AddressSpec{Multisig: config}, nonce)
 }
 
+// CanonicalBytes returns the canonical CBOR encoding of the transaction, which is what gets
+// signed by PrepareForSigning.
+//
+// Since the call body is included in the encoding verbatim, an error is returned in case it is
+// not canonically encoded itself.
+func (t *Transaction) CanonicalBytes() ([]byte, error) {
+	if len(t.Call.Body) > 0 {
+		var body interface{}
+		if err := cbor.Unmarshal(t.Call.Body, &body); err != nil {
+			return nil, fmt.Errorf("transaction: malformed call body: %w", err)
+		}
+		if !bytes.Equal(cbor.Marshal(body), t.Call.Body) {
+			return nil, fmt.Errorf("transaction: call body is not canonically encoded")
+		}
+	}
+	return cbor.Marshal(t), nil
+}
+
 func (t *Transaction) PrepareForSigning() *TransactionSigner {
 	return &TransactionSigner{
 		tx: *t,
@@ -1,13 +1,16 @@
 package types
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasisprotocol/oasis-core/go/common"
 	"github.com/oasisprotocol/oasis-core/go/common/cbor"
+	"github.com/oasisprotocol/oasis-core/go/common/crypto/hash"
 	memorySigner "github.com/oasisprotocol/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/crypto/signature/ed25519"
@@ -77,3 +80,182 @@ func TestTransactionSigning(t *testing.T) {
 	err = tx.ValidateBasic()
 	require.NoError(err, "ValidateBasic")
 }
+
+func TestTransactionDetachedSigning(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing"))
+	signer2 := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing 2"))
+	signer3 := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing 3"))
+
+	sigSpec := NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey))
+	spec := AddressSpec{Signature: &sigSpec}
+	msSpec := AddressSpec{Multisig: &MultisigConfig{
+		Signers: []MultisigSigner{
+			{PublicKey: PublicKey{PublicKey: signer.Public()}, Weight: 1},
+			{PublicKey: PublicKey{PublicKey: signer2.Public()}, Weight: 1},
+		},
+		Threshold: 2,
+	}}
+
+	tx := NewTransaction(nil, "hello.World", nil)
+	tx.AppendSignerInfo(spec, 42)
+	tx.AppendSignerInfo(msSpec, 43)
+
+	var runtimeID common.Namespace
+	_ = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	chainCtx := signature.DeriveChainContext(runtimeID, "0000000000000000000000000000000000000000000000000000000000000001")
+
+	// Produce signatures separately, as if on different devices.
+	var sigs []*Signature
+	for _, s := range []signature.Signer{signer, signer2} {
+		sig, err := tx.PrepareForSigning().ProducePartial(chainCtx, s)
+		require.NoError(err, "ProducePartial")
+
+		var decoded Signature
+		err = cbor.Unmarshal(cbor.Marshal(sig), &decoded)
+		require.NoError(err, "signature should round-trip through CBOR")
+		sigs = append(sigs, &decoded)
+	}
+	_, err := tx.PrepareForSigning().ProducePartial(chainCtx, signer3)
+	require.Error(err, "ProducePartial should fail for unknown signers")
+
+	ts := tx.PrepareForSigning()
+	require.NoError(ts.AttachSignature(spec, sigs[0]), "AttachSignature")
+	require.NoError(ts.AttachSignature(msSpec, sigs[0]), "AttachSignature multisig")
+	require.NoError(ts.AttachSignature(msSpec, sigs[1]), "AttachSignature multisig signer2")
+	require.Error(ts.AttachSignature(spec, sigs[1]), "AttachSignature should fail for mismatched public key")
+	require.Error(ts.AttachSignature(AddressSpec{Signature: &SignatureAddressSpec{}}, sigs[0]), "AttachSignature should fail for unknown address spec")
+	require.Error(ts.AttachSignature(spec, &Signature{}), "AttachSignature should fail for malformed signature")
+
+	ut := ts.UnverifiedTransaction()
+	_, err = ut.Verify(chainCtx)
+	require.NoError(err, "Verify")
+
+	// Attached signatures should be the same as the ones appended directly.
+	ts2 := tx.PrepareForSigning()
+	require.NoError(ts2.AppendSign(chainCtx, signer), "AppendSign")
+	require.NoError(ts2.AppendSign(chainCtx, signer2), "AppendSign signer2")
+	require.Equal(ts2.UnverifiedTransaction(), ut)
+}
+
+func TestTransactionCanonicalBytes(t *testing.T) {
+	require := require.New(t)
+
+	fee := &Fee{
+		Amount: NewBaseUnits(*quantity.NewFromUint64(100), NativeDenomination),
+		Gas:    1000,
+	}
+	tx := NewTransaction(fee, "test.Method", nil)
+	enc, err := tx.CanonicalBytes()
+	require.NoError(err, "CanonicalBytes")
+	// Map keys must be sorted by length first and then lexicographically.
+	require.Equal(
+		"a3"+"6176"+"01"+
+			"626169"+"a2"+
+			"627369"+"80"+
+			"63666565"+"a2"+"63676173"+"1903e8"+"66616d6f756e74"+"82"+"4164"+"40"+
+			"6463616c6c"+"a2"+"64626f6479"+"f6"+"666d6574686f64"+"6b746573742e4d6574686f64",
+		hex.EncodeToString(enc),
+	)
+
+	enc2, err := NewTransaction(fee, "test.Method", nil).CanonicalBytes()
+	require.NoError(err, "CanonicalBytes")
+	require.Equal(enc, enc2, "encoding should be deterministic")
+	require.Equal(enc, tx.PrepareForSigning().UnverifiedTransaction().Body, "signed body should be canonical")
+
+	// Encoding of struct bodies should be canonical regardless of field order.
+	type body struct {
+		B uint64 `json:"b"`
+		A uint64 `json:"a"`
+	}
+	tx = NewTransaction(fee, "test.Method", &body{B: 1, A: 2})
+	_, err = tx.CanonicalBytes()
+	require.NoError(err, "CanonicalBytes")
+
+	// Non-canonical bodies should be rejected.
+	tx.Call.Body, _ = hex.DecodeString("a2616201616102") // {"b": 1, "a": 2}
+	_, err = tx.CanonicalBytes()
+	require.Error(err, "CanonicalBytes should fail with non-canonical body")
+	tx.Call.Body, _ = hex.DecodeString("1800") // Non-minimal encoding of 0.
+	_, err = tx.CanonicalBytes()
+	require.Error(err, "CanonicalBytes should fail with non-canonical body")
+}
+
+func TestUnverifiedTransactionHash(t *testing.T) {
+	require := require.New(t)
+
+	ut := &UnverifiedTransaction{
+		Body:       []byte("body"),
+		AuthProofs: []AuthProof{{Signature: []byte{0x01}}},
+	}
+	// The hash is computed over the serialized transaction as submitted to nodes.
+	raw, _ := hex.DecodeString("8244626f647981a1697369676e61747572654101")
+	require.Equal(raw, cbor.Marshal(ut))
+	require.Equal(hash.NewFromBytes(raw), ut.Hash())
+
+	// The hash should survive carrying the transaction to a different machine.
+	var decoded UnverifiedTransaction
+	require.NoError(cbor.Unmarshal(raw, &decoded), "Unmarshal")
+	require.Equal(ut.Hash(), decoded.Hash())
+
+	ut.AuthProofs[0].Signature = []byte{0x02}
+	require.NotEqual(hash.NewFromBytes(raw), ut.Hash(), "hash should cover signatures")
+}
+
+func TestTransactionVerifyInvalid(t *testing.T) {
+	require := require.New(t)
+
+	signer := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing"))
+	signer2 := ed25519.WrapSigner(memorySigner.NewTestSigner("oasis-runtime-sdk/test-keys: tx signing 2"))
+
+	var runtimeID common.Namespace
+	_ = runtimeID.UnmarshalHex("8000000000000000000000000000000000000000000000000000000000000000")
+	chainCtx := signature.DeriveChainContext(runtimeID, "0000000000000000000000000000000000000000000000000000000000000001")
+	otherChainCtx := signature.DeriveChainContext(runtimeID, "0000000000000000000000000000000000000000000000000000000000000002")
+
+	newSignedMethod := func(method string, signers ...signature.Signer) *UnverifiedTransaction {
+		tx := NewTransaction(nil, method, nil)
+		tx.AppendAuthSignature(NewSignatureAddressSpecEd25519(signer.Public().(ed25519.PublicKey)), 42)
+		tx.AppendAuthMultisig(&MultisigConfig{
+			Signers: []MultisigSigner{
+				{PublicKey: PublicKey{PublicKey: signer.Public()}, Weight: 1},
+				{PublicKey: PublicKey{PublicKey: signer2.Public()}, Weight: 1},
+			},
+			Threshold: 2,
+		}, 43)
+		ts := tx.PrepareForSigning()
+		for _, s := range signers {
+			err := ts.AppendSign(chainCtx, s)
+			require.NoError(err, "AppendSign")
+		}
+		return ts.UnverifiedTransaction()
+	}
+	newSigned := func(signers ...signature.Signer) *UnverifiedTransaction {
+		return newSignedMethod("hello.World", signers...)
+	}
+
+	_, err := newSigned(signer, signer2).Verify(chainCtx)
+	require.NoError(err, "Verify")
+
+	_, err = newSigned(signer, signer2).Verify(otherChainCtx)
+	require.Error(err, "Verify should fail with a different chain context")
+
+	_, err = newSigned(signer).Verify(chainCtx)
+	require.Error(err, "Verify should fail with insufficient multisig weight")
+
+	ut := newSigned(signer, signer2)
+	ut.Body = newSignedMethod("hello.Other").Body
+	_, err = ut.Verify(chainCtx)
+	require.Error(err, "Verify should fail with a replaced body")
+
+	ut = newSigned(signer, signer2)
+	ut.AuthProofs = ut.AuthProofs[:1]
+	_, err = ut.Verify(chainCtx)
+	require.Error(err, "Verify should fail with missing auth proofs")
+
+	ut = newSigned(signer, signer2)
+	ut.AuthProofs[0].Signature[0] ^= 0xff
+	_, err = ut.Verify(chainCtx)
+	require.Error(err, "Verify should fail with a corrupted signature")
+}
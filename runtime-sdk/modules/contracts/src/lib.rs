@@ -12,7 +12,7 @@ use thiserror::Error;
 use oasis_contract_sdk_types::storage::StoreKind;
 use oasis_runtime_sdk::{
     self as sdk,
-    context::{Context, TxContext},
+    context::{BatchContext, Context, TxContext},
     core::common::crypto::hash::Hash,
     error, module,
     module::{CallResult, Module as _},
@@ -22,6 +22,7 @@ use oasis_runtime_sdk::{
         core::{Module as Core, API as _},
     },
     storage::{self, Store as _},
+    types::transaction,
 };
 
 mod abi;
@@ -684,6 +685,71 @@ impl<Cfg: Config> Module<Cfg> {
 
         Ok(types::CustomQueryResult(result.data))
     }
+
+    fn query_simulate_call<C: Context>(
+        ctx: &mut C,
+        args: types::SimulateCallQuery,
+    ) -> Result<types::SimulateCallQueryResult, Error> {
+        if !ctx.are_expensive_queries_allowed() {
+            return Err(Error::Forbidden);
+        }
+
+        // Load local configuration.
+        let cfg: LocalConfig = ctx.local_config(MODULE_NAME).unwrap_or_default();
+        let gas_limit = if args.gas_limit > 0 {
+            std::cmp::min(args.gas_limit, cfg.query_custom_max_gas)
+        } else {
+            cfg.query_custom_max_gas
+        };
+
+        let body = types::Call {
+            id: args.id,
+            data: args.data,
+            tokens: args.tokens,
+        };
+        let tx = transaction::Transaction {
+            version: 1,
+            call: transaction::Call {
+                format: transaction::CallFormat::Plain,
+                method: "contracts.Call".to_owned(),
+                body: cbor::to_value(body.clone()),
+            },
+            auth_info: transaction::AuthInfo {
+                signer_info: vec![transaction::SignerInfo {
+                    address_spec: transaction::AddressSpec::Internal(
+                        transaction::CallerAddress::Address(args.caller),
+                    ),
+                    nonce: 0,
+                }],
+                fee: transaction::Fee {
+                    gas: gas_limit,
+                    ..Default::default()
+                },
+            },
+        };
+
+        // Run the call in simulation mode so that all state changes are discarded.
+        ctx.with_simulation(|mut sim_ctx| {
+            sim_ctx.with_tx(0, tx, |mut tx_ctx, _call| {
+                let result = Self::tx_call(&mut tx_ctx, body)?;
+                let gas_used = gas_limit.saturating_sub(Core::remaining_tx_gas(&mut tx_ctx));
+                // Committing only affects the simulation context which is discarded afterwards.
+                let (tags, _) = tx_ctx.commit();
+                let events = tags
+                    .into_iter()
+                    .map(|tag| types::SimulatedEvent {
+                        key: tag.key,
+                        value: tag.value,
+                    })
+                    .collect();
+                Ok(types::SimulateCallQueryResult {
+                    data: result.0,
+                    gas_used,
+                    events,
+                })
+            })
+        })
+    }
 }
 
 impl<Cfg: Config> module::Module for Module<Cfg> {
@@ -694,6 +760,21 @@ impl<Cfg: Config> module::Module for Module<Cfg> {
 }
 
 impl<Cfg: Config> module::MethodHandler for Module<Cfg> {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::call("contracts.Upload"),
+            module::MethodHandlerInfo::call("contracts.Instantiate"),
+            module::MethodHandlerInfo::call("contracts.Call"),
+            module::MethodHandlerInfo::call("contracts.Upgrade"),
+            module::MethodHandlerInfo::query("contracts.Code"),
+            module::MethodHandlerInfo::query("contracts.Instance"),
+            module::MethodHandlerInfo::query("contracts.InstanceStorage"),
+            module::MethodHandlerInfo::query("contracts.PublicKey"),
+            module::MethodHandlerInfo::query("contracts.Custom"),
+            module::MethodHandlerInfo::query("contracts.SimulateCall"),
+        ]
+    }
+
     fn dispatch_call<C: TxContext>(
         ctx: &mut C,
         method: &str,
@@ -721,6 +802,9 @@ impl<Cfg: Config> module::MethodHandler for Module<Cfg> {
             }
             "contracts.PublicKey" => module::dispatch_query(ctx, args, Self::query_public_key),
             "contracts.Custom" => module::dispatch_query(ctx, args, Self::query_custom),
+            "contracts.SimulateCall" => {
+                module::dispatch_query(ctx, args, Self::query_simulate_call)
+            }
             _ => module::DispatchResult::Unhandled(args),
         }
     }
@@ -244,6 +244,51 @@ pub struct CustomQuery {
 #[cbor(transparent)]
 pub struct CustomQueryResult(pub Vec<u8>);
 
+/// Simulated contract call query.
+#[derive(Clone, Debug, cbor::Encode, cbor::Decode)]
+pub struct SimulateCallQuery {
+    /// Address of the caller.
+    pub caller: Address,
+
+    /// Instance identifier.
+    pub id: InstanceId,
+
+    /// Call arguments.
+    pub data: Vec<u8>,
+
+    /// Tokens that should be sent to the contract as part of the call.
+    #[cbor(optional, default)]
+    pub tokens: Vec<token::BaseUnits>,
+
+    /// Gas limit for the simulated call. If zero, the node-configured custom query gas limit
+    /// is used.
+    #[cbor(optional, default)]
+    pub gas_limit: u64,
+}
+
+/// Simulated contract call result.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct SimulateCallQueryResult {
+    /// Data returned by the contract call.
+    pub data: Vec<u8>,
+
+    /// Amount of gas used by the call.
+    pub gas_used: u64,
+
+    /// Events that would be emitted by the call.
+    pub events: Vec<SimulatedEvent>,
+}
+
+/// An event emitted during a simulated call.
+#[derive(Clone, Debug, Default, cbor::Encode, cbor::Decode)]
+pub struct SimulatedEvent {
+    /// Raw event key.
+    pub key: Vec<u8>,
+
+    /// Raw event value.
+    pub value: Vec<u8>,
+}
+
 /// An event emitted from a contract, wrapped to include additional metadata.
 #[derive(Clone, Debug, cbor::Encode, cbor::Decode)]
 pub struct ContractEvent {
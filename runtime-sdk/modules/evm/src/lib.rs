@@ -617,6 +617,17 @@ impl<Cfg: Config> Module<Cfg> {
 }
 
 impl<Cfg: Config> module::MethodHandler for Module<Cfg> {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::call("evm.Create"),
+            module::MethodHandlerInfo::call("evm.Call"),
+            module::MethodHandlerInfo::query("evm.Storage"),
+            module::MethodHandlerInfo::query("evm.Code"),
+            module::MethodHandlerInfo::query("evm.Balance"),
+            module::MethodHandlerInfo::query("evm.SimulateCall"),
+        ]
+    }
+
     fn dispatch_call<C: TxContext>(
         ctx: &mut C,
         method: &str,
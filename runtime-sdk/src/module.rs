@@ -145,8 +145,51 @@ where
     })())
 }
 
+/// Kind of a method supported by a method handler.
+#[derive(Clone, Copy, Debug, PartialEq, Eq, cbor::Encode, cbor::Decode)]
+#[repr(u8)]
+pub enum MethodHandlerKind {
+    /// Callable method.
+    Call = 1,
+    /// Query method.
+    Query = 2,
+}
+
+/// Information about a method supported by a method handler.
+#[derive(Clone, Debug, PartialEq, Eq, cbor::Encode, cbor::Decode)]
+pub struct MethodHandlerInfo {
+    /// Method kind.
+    pub kind: MethodHandlerKind,
+    /// Fully qualified method name (e.g. `accounts.Transfer`).
+    pub name: String,
+}
+
+impl MethodHandlerInfo {
+    /// Information about a callable method with the given name.
+    pub fn call(name: &str) -> Self {
+        Self {
+            kind: MethodHandlerKind::Call,
+            name: name.to_owned(),
+        }
+    }
+
+    /// Information about a query method with the given name.
+    pub fn query(name: &str) -> Self {
+        Self {
+            kind: MethodHandlerKind::Query,
+            name: name.to_owned(),
+        }
+    }
+}
+
 /// Method handler.
 pub trait MethodHandler {
+    /// Return information about all the methods supported by the handler.
+    ///
+    /// Every method dispatched by the handler must be listed as the result is used by clients to
+    /// detect the runtime's capabilities (see the `core.SupportedMethods` query).
+    fn supported_methods() -> Vec<MethodHandlerInfo>;
+
     /// Add storage prefixes to prefetch.
     fn prefetch(
         _prefixes: &mut BTreeSet<Prefix>,
@@ -191,6 +234,12 @@ pub trait MethodHandler {
 
 #[impl_for_tuples(30)]
 impl MethodHandler for Tuple {
+    fn supported_methods() -> Vec<MethodHandlerInfo> {
+        let mut methods = vec![];
+        for_tuples!( #( methods.extend(Tuple::supported_methods()); )* );
+        methods
+    }
+
     fn prefetch(
         prefixes: &mut BTreeSet<Prefix>,
         method: &str,
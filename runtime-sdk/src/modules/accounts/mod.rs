@@ -711,6 +711,16 @@ impl module::Module for Module {
 }
 
 impl module::MethodHandler for Module {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::call("accounts.Transfer"),
+            module::MethodHandlerInfo::query("accounts.Nonce"),
+            module::MethodHandlerInfo::query("accounts.Balances"),
+            module::MethodHandlerInfo::query("accounts.Addresses"),
+            module::MethodHandlerInfo::query("accounts.DenominationInfo"),
+        ]
+    }
+
     fn prefetch(
         prefixes: &mut BTreeSet<Prefix>,
         method: &str,
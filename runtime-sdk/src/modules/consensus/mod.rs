@@ -353,6 +353,10 @@ impl module::Module for Module {
 }
 
 impl module::MethodHandler for Module {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![module::MethodHandlerInfo::query("consensus.Parameters")]
+    }
+
     fn dispatch_query<C: Context>(
         ctx: &mut C,
         method: &str,
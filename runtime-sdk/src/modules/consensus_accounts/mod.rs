@@ -373,6 +373,15 @@ impl<Accounts: modules::accounts::API, Consensus: modules::consensus::API> modul
 impl<Accounts: modules::accounts::API, Consensus: modules::consensus::API> module::MethodHandler
     for Module<Accounts, Consensus>
 {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::call("consensus.Deposit"),
+            module::MethodHandlerInfo::call("consensus.Withdraw"),
+            module::MethodHandlerInfo::query("consensus.Balance"),
+            module::MethodHandlerInfo::query("consensus.Account"),
+        ]
+    }
+
     fn prefetch(
         prefixes: &mut BTreeSet<Prefix>,
         method: &str,
@@ -10,7 +10,7 @@ use crate::{
     callformat,
     context::{BatchContext, Context, TxContext},
     dispatcher, error,
-    module::{self, InvariantHandler as _, Module as _},
+    module::{self, InvariantHandler as _, MethodHandler as _, Module as _},
     types::{
         token,
         transaction::{
@@ -415,6 +415,26 @@ impl Module {
 
         Ok(params.min_gas_price)
     }
+
+    /// Query the module parameters.
+    fn query_parameters<C: Context>(ctx: &mut C, _args: ()) -> Result<Parameters, Error> {
+        Ok(Self::params(ctx.runtime_state()))
+    }
+
+    /// Query the gas costs.
+    fn query_gas_costs<C: Context>(ctx: &mut C, _args: ()) -> Result<GasCosts, Error> {
+        let params = Self::params(ctx.runtime_state());
+
+        Ok(params.gas_costs)
+    }
+
+    /// Query the methods supported by the runtime.
+    fn query_supported_methods<C: Context>(
+        _ctx: &mut C,
+        _args: (),
+    ) -> Result<Vec<module::MethodHandlerInfo>, Error> {
+        Ok(<C::Runtime as Runtime>::Modules::supported_methods())
+    }
 }
 
 impl module::Module for Module {
@@ -537,6 +557,18 @@ impl module::MigrationHandler for Module {
 }
 
 impl module::MethodHandler for Module {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::query("core.EstimateGas"),
+            module::MethodHandlerInfo::query("core.CheckInvariants"),
+            module::MethodHandlerInfo::query("core.CallDataPublicKey"),
+            module::MethodHandlerInfo::query("core.MinGasPrice"),
+            module::MethodHandlerInfo::query("core.GasCosts"),
+            module::MethodHandlerInfo::query("core.Parameters"),
+            module::MethodHandlerInfo::query("core.SupportedMethods"),
+        ]
+    }
+
     fn dispatch_query<C: Context>(
         ctx: &mut C,
         method: &str,
@@ -551,6 +583,11 @@ impl module::MethodHandler for Module {
                 module::dispatch_query(ctx, args, Self::query_calldata_public_key)
             }
             "core.MinGasPrice" => module::dispatch_query(ctx, args, Self::query_min_gas_price),
+            "core.GasCosts" => module::dispatch_query(ctx, args, Self::query_gas_costs),
+            "core.Parameters" => module::dispatch_query(ctx, args, Self::query_parameters),
+            "core.SupportedMethods" => {
+                module::dispatch_query(ctx, args, Self::query_supported_methods)
+            }
             _ => module::DispatchResult::Unhandled(args),
         }
     }
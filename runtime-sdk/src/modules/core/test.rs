@@ -16,7 +16,7 @@ use crate::{
     },
 };
 
-use super::{types, Module as Core, Parameters, API as _, GAS_WEIGHT_NAME};
+use super::{types, GasCosts, Module as Core, Parameters, API as _, GAS_WEIGHT_NAME};
 
 #[test]
 fn test_use_gas() {
@@ -135,6 +135,37 @@ fn test_query_min_gas_price() {
     assert!(*mgp.get(&token::Denomination::NATIVE).unwrap() == 123);
 }
 
+#[test]
+fn test_query_parameters() {
+    let mut mock = mock::Mock::default();
+    let mut ctx = mock.create_ctx();
+    Core::set_params(
+        ctx.runtime_state(),
+        Parameters {
+            max_batch_gas: 10000,
+            max_tx_signers: 8,
+            max_multisig_signers: 8,
+            gas_costs: GasCosts {
+                tx_byte: 1,
+                auth_signature: 10,
+                auth_multisig_signer: 20,
+                callformat_x25519_deoxysii: 30,
+            },
+            min_gas_price: Default::default(),
+        },
+    );
+
+    let params = Core::query_parameters(&mut ctx, ()).expect("query_parameters should succeed");
+    assert_eq!(params.max_batch_gas, 10000);
+    assert_eq!(params.max_tx_signers, 8);
+
+    let gc = Core::query_gas_costs(&mut ctx, ()).expect("query_gas_costs should succeed");
+    assert_eq!(gc.tx_byte, 1);
+    assert_eq!(gc.auth_signature, 10);
+    assert_eq!(gc.auth_multisig_signer, 20);
+    assert_eq!(gc.callformat_x25519_deoxysii, 30);
+}
+
 // Module that implements the gas waster method.
 struct GasWasterModule;
 
@@ -151,6 +182,10 @@ impl module::Module for GasWasterModule {
 }
 
 impl module::MethodHandler for GasWasterModule {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![module::MethodHandlerInfo::call(Self::METHOD_WASTE_GAS)]
+    }
+
     fn dispatch_call<C: TxContext>(
         ctx: &mut C,
         method: &str,
@@ -592,3 +627,15 @@ fn test_min_gas_price() {
         Core::before_handle_call(&mut tx_ctx, &call).expect("gas price should be ok");
     });
 }
+
+#[test]
+fn test_supported_methods() {
+    let methods =
+        <<GasWasterRuntime as Runtime>::Modules as module::MethodHandler>::supported_methods();
+    assert!(methods.contains(&module::MethodHandlerInfo::query("core.EstimateGas")));
+    assert!(methods.contains(&module::MethodHandlerInfo::query("core.SupportedMethods")));
+    assert!(methods.contains(&module::MethodHandlerInfo::call(
+        GasWasterModule::METHOD_WASTE_GAS
+    )));
+    assert!(!methods.contains(&module::MethodHandlerInfo::call("core.EstimateGas")));
+}
@@ -101,6 +101,10 @@ impl<Accounts: modules::accounts::API> module::Module for Module<Accounts> {
 }
 
 impl<Accounts: modules::accounts::API> module::MethodHandler for Module<Accounts> {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![module::MethodHandlerInfo::query("rewards.Parameters")]
+    }
+
     fn dispatch_query<C: Context>(
         ctx: &mut C,
         method: &str,
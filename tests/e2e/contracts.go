@@ -47,8 +47,11 @@ func ContractsTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.ClientCo
 		return fmt.Errorf("failed to get nonce: %w", err)
 	}
 
-	tb := ct.Upload(contracts.ABIOasisV1, contracts.Policy{Everyone: &struct{}{}}, helloContractCode).
-		SetFeeGas(1_000_000).
+	tb, err := ct.Upload(contracts.ABIOasisV1, contracts.Policy{Everyone: &struct{}{}}, helloContractCode)
+	if err != nil {
+		return fmt.Errorf("invalid hello contract code: %w", err)
+	}
+	tb.SetFeeGas(1_000_000).
 		AppendAuthSignature(testing.Alice.SigSpec, nonce)
 	_ = tb.AppendSign(ctx, signer)
 	var upload contracts.UploadResult
@@ -75,13 +78,11 @@ func ContractsTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.ClientCo
 	}
 
 	// Call a method on the contract.
-	tb = ct.Call(
+	tb = ct.CallRaw(
 		instance.ID,
-		map[string]map[string]string{
-			"say_hello": {
-				"who": "e2e test",
-			},
-		},
+		contracts.EncodeRequest("say_hello", map[string]string{
+			"who": "e2e test",
+		}),
 		[]types.BaseUnits{},
 	).
 		SetFeeGas(1_000_000).
@@ -92,20 +93,23 @@ func ContractsTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.ClientCo
 		return fmt.Errorf("failed to call contract: %w", err)
 	}
 
-	var result map[string]map[string]string
-	if err = cbor.Unmarshal(rawResult, &result); err != nil {
+	var result map[string]string
+	if err = contracts.DecodeResponse(rawResult, "hello", &result); err != nil {
 		return fmt.Errorf("failed to decode contract result: %w", err)
 	}
 
-	if result["hello"]["greeting"] != fmt.Sprintf("hello e2e test (%d)", counter) {
+	if result["greeting"] != fmt.Sprintf("hello e2e test (%d)", counter) {
 		return fmt.Errorf("unexpected result from contract: %+v", result)
 	}
 	// Calling say_hello bumps the counter.
 	counter++
 
 	// Upload OAS20 contract code.
-	tb = ct.Upload(contracts.ABIOasisV1, contracts.Policy{Everyone: &struct{}{}}, oas20ContractCode).
-		SetFeeGas(1_000_000).
+	tb, err = ct.Upload(contracts.ABIOasisV1, contracts.Policy{Everyone: &struct{}{}}, oas20ContractCode)
+	if err != nil {
+		return fmt.Errorf("invalid OAS20 contract code: %w", err)
+	}
+	tb.SetFeeGas(1_000_000).
 		AppendAuthSignature(testing.Alice.SigSpec, nonce+3)
 	_ = tb.AppendSign(ctx, signer)
 	var uploadOas20 contracts.UploadResult
@@ -260,13 +264,11 @@ OUTER:
 	counter += 13
 
 	// Check counter.
-	tb = ct.Call(
+	tb = ct.CallRaw(
 		instance.ID,
-		map[string]map[string]string{
-			"say_hello": {
-				"who": "e2e test",
-			},
-		},
+		contracts.EncodeRequest("say_hello", map[string]string{
+			"who": "e2e test",
+		}),
 		[]types.BaseUnits{},
 	).
 		SetFeeGas(1_000_000).
@@ -276,11 +278,11 @@ OUTER:
 		return fmt.Errorf("failed to call hello contract: %w", err)
 	}
 
-	if err = cbor.Unmarshal(rawResult, &result); err != nil {
+	if err = contracts.DecodeResponse(rawResult, "hello", &result); err != nil {
 		return fmt.Errorf("failed to decode contract result: %w", err)
 	}
 
-	if result["hello"]["greeting"] != fmt.Sprintf("hello e2e test (%d)", counter) {
+	if result["greeting"] != fmt.Sprintf("hello e2e test (%d)", counter) {
 		return fmt.Errorf("unexpected result from contract: %+v", result)
 	}
 	// Calling say_hello bumps the counter.
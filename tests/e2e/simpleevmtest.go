@@ -6,11 +6,11 @@ import (
 	_ "embed"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"google.golang.org/grpc"
 
-	"github.com/oasisprotocol/oasis-core/go/common/cbor"
 	"github.com/oasisprotocol/oasis-core/go/common/logging"
 	"github.com/oasisprotocol/oasis-core/go/common/quantity"
 
@@ -19,6 +19,8 @@ import (
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/accounts"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/core"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm/abi"
+	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/modules/evm/tokens"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/testing"
 	"github.com/oasisprotocol/oasis-sdk/client-sdk/go/types"
 
@@ -39,40 +41,38 @@ func evmCreate(ctx context.Context, rtc client.RuntimeClient, e evm.V1, signer s
 	txB := e.Create(value, initCode)
 
 	// Check if gas estimation works.
-	gasLimit, err := core.NewV1(rtc).EstimateGasForCaller(ctx, client.RoundLatest, types.CallerAddress{Address: &testing.Dave.Address}, txB.GetTransaction())
+	gasLimit, err := core.NewV1(rtc).
+		WithDefaultCaller(types.CallerAddress{Address: &testing.Dave.Address}).
+		EstimateGas(ctx, client.RoundLatest, txB.GetTransaction())
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 
 	tx := txB.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(gasPrice * gasLimit), types.NativeDenomination)).GetTransaction()
-	result, err := txgen.SignAndSubmitTx(ctx, rtc, signer, *tx, gasLimit)
-	if err != nil {
-		return nil, err
-	}
 	var out []byte
-	if err = cbor.Unmarshal(result, &out); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal evmCreate result: %w", err)
+	if _, err = txgen.SignAndSubmitTxDecoded(ctx, rtc, signer, *tx, gasLimit, &out); err != nil {
+		return nil, err
 	}
 	return out, nil
 }
 
 func evmCall(ctx context.Context, rtc client.RuntimeClient, e evm.V1, signer signature.Signer, address []byte, value []byte, data []byte, gasPrice uint64) ([]byte, error) {
-	txB := e.Call(address, value, data)
+	return evmSubmitCall(ctx, rtc, signer, e.Call(address, value, data), gasPrice)
+}
 
+func evmSubmitCall(ctx context.Context, rtc client.RuntimeClient, signer signature.Signer, txB *client.TransactionBuilder, gasPrice uint64) ([]byte, error) {
 	// Check if ETH gas estimation works.
-	gasLimit, err := core.NewV1(rtc).EstimateGasForCaller(ctx, client.RoundLatest, types.CallerAddress{EthAddress: &testing.Dave.EthAddress}, txB.GetTransaction())
+	gasLimit, err := core.NewV1(rtc).
+		WithDefaultCaller(types.CallerAddress{EthAddress: &testing.Dave.EthAddress}).
+		EstimateGas(ctx, client.RoundLatest, txB.GetTransaction())
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 
 	tx := txB.SetFeeAmount(types.NewBaseUnits(*quantity.NewFromUint64(gasPrice * gasLimit), types.NativeDenomination)).GetTransaction()
-	result, err := txgen.SignAndSubmitTx(ctx, rtc, signer, *tx, gasLimit)
-	if err != nil {
-		return nil, err
-	}
 	var out []byte
-	if err = cbor.Unmarshal(result, &out); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal evmCall result: %w", err)
+	if _, err = txgen.SignAndSubmitTxDecoded(ctx, rtc, signer, *tx, gasLimit, &out); err != nil {
+		return nil, err
 	}
 	return out, nil
 }
@@ -498,34 +498,25 @@ func SimpleERC20EVMTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.Cli
 
 	log.Info("evmCreate finished", "contract_addr", hex.EncodeToString(contractAddr))
 
-	// This is the hash of the "name()" method of the contract.
-	// You can get this by clicking on "Compilation details" and then
-	// looking at the "Function hashes" section.
-	// Method calls must be zero-padded to a multiple of 32 bytes.
-	nameMethod, err := hex.DecodeString("06fdde03" + strings.Repeat("0", 64-8))
-	if err != nil {
-		return err
-	}
+	token := tokens.NewERC20(rtc, contractAddr)
 
-	// Call the name method.
-	callResult, err := evmCall(ctx, rtc, e, signer, contractAddr, zero, nameMethod, gasPrice)
+	// Query the token name.
+	name, err := token.Name(ctx, client.RoundLatest)
 	if err != nil {
-		return fmt.Errorf("evmCall:name failed: %w", err)
+		return fmt.Errorf("ERC20:name failed: %w", err)
 	}
+	log.Info("ERC20:name finished", "name", name)
 
-	resName := hex.EncodeToString(callResult)
-	log.Info("evmCall:name finished", "call_result", resName)
-
-	if len(resName) != 192 {
-		return fmt.Errorf("returned value has wrong length (expected 192, got %d)", len(resName))
-	}
-	if resName[127:136] != "454657374" {
-		// The returned string is packed as length (4) + "Test" in hex.
-		return fmt.Errorf("returned value is incorrect (expected '454657374', got '%s')", resName[127:136])
+	if name != "Test" {
+		return fmt.Errorf("returned name is incorrect (expected 'Test', got '%s')", name)
 	}
 
 	// Assemble the transfer(0x123, 0x42) call.
-	transferMethod, err := hex.DecodeString("a9059cbb" + strings.Repeat("0", 64-3) + "123" + strings.Repeat("0", 64-2) + "42")
+	var to [20]byte
+	to[18] = 0x01
+	to[19] = 0x23
+	amount := big.NewInt(0x42)
+	transferMethod, err := abi.PackMethod("transfer(address,uint256)", to, amount)
 	if err != nil {
 		return err
 	}
@@ -545,13 +536,17 @@ func SimpleERC20EVMTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.Cli
 	}
 
 	// Call transfer(0x123, 0x42).
-	callResult, err = evmCall(ctx, rtc, e, signer, contractAddr, zero, transferMethod, gasPrice)
+	txB, err := token.Transfer(to[:], amount)
 	if err != nil {
-		return fmt.Errorf("evmCall:transfer failed: %w", err)
+		return err
+	}
+	callResult, err := evmSubmitCall(ctx, rtc, signer, txB, gasPrice)
+	if err != nil {
+		return fmt.Errorf("ERC20:transfer failed: %w", err)
 	}
 
 	resTransfer := hex.EncodeToString(callResult)
-	log.Info("evmCall:transfer finished", "call_result", resTransfer)
+	log.Info("ERC20:transfer finished", "call_result", resTransfer)
 
 	// Return value should be true.
 	if resTransfer != strings.Repeat("0", 64-1)+"1" {
@@ -582,22 +577,16 @@ func SimpleERC20EVMTest(sc *RuntimeScenario, log *logging.Logger, conn *grpc.Cli
 		return fmt.Errorf("data in event is wrong")
 	}
 
-	// Call balanceOf(0x123).
-	balanceMethod, err := hex.DecodeString("70a08231" + strings.Repeat("0", 64-3) + "123")
+	// Query balanceOf(0x123).
+	balance, err := token.BalanceOf(ctx, client.RoundLatest, to[:])
 	if err != nil {
-		return err
+		return fmt.Errorf("ERC20:balanceOf failed: %w", err)
 	}
-	callResult, err = evmCall(ctx, rtc, e, signer, contractAddr, zero, balanceMethod, gasPrice)
-	if err != nil {
-		return fmt.Errorf("evmCall:balanceOf failed: %w", err)
-	}
-
-	resBalance := hex.EncodeToString(callResult)
-	log.Info("evmCall:balanceOf finished", "call_result", resBalance)
+	log.Info("ERC20:balanceOf finished", "balance", balance)
 
 	// Balance should match the amount we transferred.
-	if resBalance != strings.Repeat("0", 64-2)+"42" {
-		return fmt.Errorf("return value of balanceOf method call should be 0x42")
+	if balance.Cmp(amount) != 0 {
+		return fmt.Errorf("balance should be 0x42 (got %s)", balance)
 	}
 
 	return nil
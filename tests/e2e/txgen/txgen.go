@@ -103,6 +103,40 @@ func CheckInvariants(ctx context.Context, rtc client.RuntimeClient) error {
 // SignAndSubmitTx signs and submits the given transaction.
 // Gas estimation is done automatically.
 func SignAndSubmitTx(ctx context.Context, rtc client.RuntimeClient, signer signature.Signer, tx types.Transaction, extraGas uint64) (cbor.RawMessage, error) {
+	stx, err := signTx(ctx, rtc, signer, tx, extraGas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submit the signed transaction.
+	var result cbor.RawMessage
+	if result, err = rtc.SubmitTx(ctx, stx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SignAndSubmitTxDecoded signs and submits the given transaction and decodes the call result
+// into rsp. Gas estimation is done automatically.
+func SignAndSubmitTxDecoded(ctx context.Context, rtc client.RuntimeClient, signer signature.Signer, tx types.Transaction, extraGas uint64, rsp interface{}) (*client.TransactionMeta, error) {
+	stx, err := signTx(ctx, rtc, signer, tx, extraGas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submit the signed transaction.
+	meta, err := rtc.SubmitTxMetaDecoded(ctx, stx, rsp)
+	if err != nil {
+		return nil, err
+	}
+	if meta.CheckTxError != nil {
+		return nil, meta.CheckTxError
+	}
+	return meta, nil
+}
+
+// signTx signs the given transaction after setting the signer's nonce and estimating gas.
+func signTx(ctx context.Context, rtc client.RuntimeClient, signer signature.Signer, tx types.Transaction, extraGas uint64) (*types.UnverifiedTransaction, error) {
 	// Get chain context.
 	chainCtx, err := GetChainContext(ctx, rtc)
 	if err != nil {
@@ -125,13 +159,7 @@ func SignAndSubmitTx(ctx context.Context, rtc client.RuntimeClient, signer signa
 	if err = stx.AppendSign(chainCtx, signer); err != nil {
 		return nil, err
 	}
-
-	// Submit the signed transaction.
-	var result cbor.RawMessage
-	if result, err = rtc.SubmitTx(ctx, stx.UnverifiedTransaction()); err != nil {
-		return nil, err
-	}
-	return result, nil
+	return stx.UnverifiedTransaction(), nil
 }
 
 // CreateAndFundAccount creates a new account and funds it using the
@@ -90,6 +90,13 @@ impl<Accounts: modules::accounts::API> module::Module for Module<Accounts> {
 
 /// Module methods.
 impl<Accounts: modules::accounts::API> module::MethodHandler for Module<Accounts> {
+    fn supported_methods() -> Vec<module::MethodHandlerInfo> {
+        vec![
+            module::MethodHandlerInfo::call("benchmarks.accounts.Mint"),
+            module::MethodHandlerInfo::call("benchmarks.accounts.Transfer"),
+        ]
+    }
+
     fn prefetch(
         prefixes: &mut BTreeSet<Prefix>,
         method: &str,
@@ -146,6 +146,15 @@ impl sdk::module::BlockHandler for Module {}
 impl sdk::module::InvariantHandler for Module {}
 
 impl sdk::module::MethodHandler for Module {
+    fn supported_methods() -> Vec<sdk::module::MethodHandlerInfo> {
+        vec![
+            sdk::module::MethodHandlerInfo::call("keyvalue.Insert"),
+            sdk::module::MethodHandlerInfo::call("keyvalue.Remove"),
+            sdk::module::MethodHandlerInfo::call("keyvalue.GetCreateKey"),
+            sdk::module::MethodHandlerInfo::query("keyvalue.Get"),
+        ]
+    }
+
     fn dispatch_call<C: TxContext>(
         ctx: &mut C,
         method: &str,